@@ -0,0 +1,152 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultMaxSize is the size in bytes the log is rotated at
+	DefaultMaxSize = 100 << 20
+	// DefaultBackups is the number of the rotated logs kept, i.e. audit.log.1 to audit.log.5
+	DefaultBackups = 5
+)
+
+const (
+	KindStatement = "statement"
+	KindConsole   = "console"
+)
+
+// RedactedValue replaces the values of the parameters if they are not included
+const RedactedValue = "<redacted>"
+
+// Entry is a line of the audit log
+type Entry struct {
+	Timestamp time.Time `json:"timestamp"`
+	User      string    `json:"user"`
+	Host      string    `json:"host"`
+	Space     string    `json:"space"`
+	// Kind is a statement sent to the server, or a console command
+	Kind      string                 `json:"kind"`
+	Statement string                 `json:"statement"`
+	Params    map[string]interface{} `json:"params,omitempty"`
+	ErrorCode int                    `json:"error_code"`
+	Error     string                 `json:"error,omitempty"`
+	// Latency is the server latency of a statement, or the time spent by a console command
+	LatencyUs int64 `json:"latency_us"`
+	Rows      int   `json:"rows"`
+}
+
+// Logger appends the entries to a file as JSON Lines, the file is rotated when it exceeds MaxSize
+type Logger struct {
+	path string
+	// MaxSize is the size in bytes to rotate at, 0 means never rotating
+	MaxSize int64
+	// Backups is the number of the rotated files kept
+	Backups int
+	// IncludeParams is whether to write the values of the parameters, they are redacted otherwise
+	IncludeParams bool
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+// NewLogger opens the log for appending, it's created with mode 0600 if not existed
+func NewLogger(path string) (*Logger, error) {
+	l := &Logger{path: path, MaxSize: DefaultMaxSize, Backups: DefaultBackups}
+	if err := l.open(); err != nil {
+		return nil, err
+	}
+	return l, nil
+}
+
+func (l *Logger) open() error {
+	f, err := os.OpenFile(l.path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	l.file, l.size = f, info.Size()
+	return nil
+}
+
+// rotate renames the log to path.1, the older ones are shifted and the oldest one is removed.
+// The current log is reopened if the renaming fails, so the later entries are still written.
+func (l *Logger) rotate() error {
+	err := l.file.Close()
+	if err == nil {
+		err = l.shift()
+	}
+	if err != nil {
+		if reopenErr := l.open(); reopenErr != nil {
+			return fmt.Errorf("%s, and reopen failed, %s", err.Error(), reopenErr.Error())
+		}
+		return err
+	}
+	return l.open()
+}
+
+// shift renames the log and the backups to the next numbers
+func (l *Logger) shift() error {
+	for i := l.Backups - 1; i >= 1; i-- {
+		from := fmt.Sprintf("%s.%d", l.path, i)
+		if _, err := os.Stat(from); err == nil {
+			if err := os.Rename(from, fmt.Sprintf("%s.%d", l.path, i+1)); err != nil {
+				return err
+			}
+		}
+	}
+	if l.Backups > 0 {
+		return os.Rename(l.path, l.path+".1")
+	}
+	return os.Remove(l.path)
+}
+
+// Log writes the entry, the values of the parameters are redacted unless IncludeParams is set
+func (l *Logger) Log(e Entry) error {
+	if !l.IncludeParams && len(e.Params) > 0 {
+		redacted := make(map[string]interface{}, len(e.Params))
+		for k := range e.Params {
+			redacted[k] = RedactedValue
+		}
+		e.Params = redacted
+	}
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	// The entry is still written to the current log if the rotation fails
+	var rotateErr error
+	if l.MaxSize > 0 && l.size > 0 && l.size+int64(len(line)) > l.MaxSize {
+		rotateErr = l.rotate()
+	}
+	n, err := l.file.Write(line)
+	l.size += int64(n)
+	if rotateErr != nil {
+		return rotateErr
+	}
+	return err
+}
+
+func (l *Logger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.file.Close()
+}
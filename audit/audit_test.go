@@ -0,0 +1,129 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// readStatements returns the statements of the entries in the log file
+func readStatements(t *testing.T, path string) []string {
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	var statements []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("invalid line %q, %s", scanner.Text(), err)
+		}
+		statements = append(statements, e.Statement)
+	}
+	return statements
+}
+
+func TestLoggerRotate(t *testing.T) {
+	cases := []struct {
+		backups int
+		entries int
+		// the statements in the log and the backups, i.e. path, path.1, path.2
+		want [][]string
+	}{
+		{backups: 2, entries: 2, want: [][]string{{"s0", "s1"}}},
+		{backups: 2, entries: 3, want: [][]string{{"s2"}, {"s0", "s1"}}},
+		{backups: 2, entries: 7, want: [][]string{{"s6"}, {"s4", "s5"}, {"s2", "s3"}}},
+		// The oldest ones are removed without backups
+		{backups: 0, entries: 5, want: [][]string{{"s4"}}},
+	}
+	for _, c := range cases {
+		path := filepath.Join(t.TempDir(), "audit.log")
+		l, err := NewLogger(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		line, _ := json.Marshal(Entry{Statement: "s0"})
+		// Two entries fit in the file
+		l.MaxSize, l.Backups = int64(len(line)+1)*2, c.backups
+		for i := 0; i < c.entries; i++ {
+			if err := l.Log(Entry{Statement: fmt.Sprintf("s%d", i)}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		l.Close()
+		for i, want := range c.want {
+			name := path
+			if i > 0 {
+				name = fmt.Sprintf("%s.%d", path, i)
+			}
+			if got := readStatements(t, name); !reflect.DeepEqual(got, want) {
+				t.Errorf("backups %d, entries %d: %s = %v, want %v", c.backups, c.entries, filepath.Base(name), got, want)
+			}
+		}
+		if _, err := os.Stat(fmt.Sprintf("%s.%d", path, len(c.want))); !os.IsNotExist(err) {
+			t.Errorf("backups %d, entries %d: %s.%d is kept", c.backups, c.entries, filepath.Base(path), len(c.want))
+		}
+	}
+}
+
+func TestLoggerRotateFailed(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	// The log can't be renamed to a directory which is not empty
+	if err := os.MkdirAll(filepath.Join(path+".1", "dir"), 0700); err != nil {
+		t.Fatal(err)
+	}
+	l, err := NewLogger(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+	l.MaxSize, l.Backups = 1, 1
+	if err := l.Log(Entry{Statement: "s0"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Log(Entry{Statement: "s1"}); err == nil {
+		t.Errorf("Log succeeded when the rotation failed, want an error")
+	}
+	if got, want := readStatements(t, path), []string{"s0", "s1"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("log after the failed rotation = %v, want %v", got, want)
+	}
+}
+
+func TestLoggerParams(t *testing.T) {
+	for _, include := range []bool{false, true} {
+		path := filepath.Join(t.TempDir(), "audit.log")
+		l, err := NewLogger(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		l.IncludeParams = include
+		l.Log(Entry{Statement: "YIELD $p", Params: map[string]interface{}{"p": "value"}})
+		l.Close()
+		content, _ := os.ReadFile(path)
+		var e Entry
+		if err := json.Unmarshal(content, &e); err != nil {
+			t.Fatal(err)
+		}
+		want := RedactedValue
+		if include {
+			want = "value"
+		}
+		if e.Params["p"] != want {
+			t.Errorf("IncludeParams %t: param = %v, want %v", include, e.Params["p"], want)
+		}
+		if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+			t.Errorf("mode of the log = %v, want 0600", info.Mode().Perm())
+		}
+	}
+}
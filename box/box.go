@@ -2,6 +2,8 @@
 
 package box
 
+import "sort"
+
 type embedBox struct {
 	storage map[string][]byte
 }
@@ -51,3 +53,13 @@ func Get(file string) []byte {
 func Has(file string) bool {
 	return box.Has(file)
 }
+
+// List the files in box
+func List() []string {
+	files := make([]string, 0, len(box.storage))
+	for file := range box.storage {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files
+}
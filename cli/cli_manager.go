@@ -6,7 +6,6 @@
 package cli
 
 import (
-	"fmt"
 	"strings"
 )
 
@@ -20,18 +19,22 @@ type Cli interface {
 	GetSpace() string
 	PlayingData(bool)
 	IsPlayingData() bool
+	// Position returns the number of the statements read and the line where the last one starts,
+	// the line is 0 if it is not known
+	Position() (statements int, line int)
 	Close()
 }
 
 type status struct {
 	// prompt
-	historyFile string
 	user        string
 	space       string
 	respErr     string
 	playingData bool
 	promptLen   int
 	promptColor int
+	// prompt is the template of the prompt, the default one is used if it's nil
+	prompt *Prompt
 
 	// multi-line seperated by '\' or enclosed in triple quotes
 	line string
@@ -76,18 +79,18 @@ func (stat *status) checkJoined(input string) {
 }
 
 func (stat *status) nebulaPrompt() string {
-	//ttyColor := prompter.color + 31
-	//prompter.color = (prompter.color + 1) % 6
-	prompt := ""
-	//prompt += fmt.Sprintf("\033[%v;1m", ttyColor)
 	if stat.joinedByTripleQuotes || stat.joinedByBackSlash {
-		prompt += strings.Repeat(" ", stat.promptLen-3)
-		prompt += "-> "
-	} else {
-		promptString := fmt.Sprintf("(%s@nebula) [%s]> ", stat.user, stat.space)
-		stat.promptLen = len(promptString)
-		prompt += promptString
+		indent := stat.promptLen - 3
+		if indent < 0 {
+			indent = 0
+		}
+		return strings.Repeat(" ", indent) + "-> "
+	}
+	p := stat.prompt
+	if p == nil {
+		p = &Prompt{template: DefaultPrompt}
 	}
-	//prompt += "\033[0m"
+	prompt := p.Render(stat.user, stat.space)
+	stat.promptLen = promptWidth(prompt)
 	return prompt
 }
@@ -0,0 +1,97 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/c-bata/go-prompt"
+)
+
+// editor returns the command of $VISUAL or $EDITOR, which may have arguments, i.e. "code --wait"
+func editor() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(env)); len(fields) > 0 {
+			return fields
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+	return []string{"vi"}
+}
+
+// Edit opens the text in the editor through a temp file, and returns the text saved
+func Edit(text string) (string, error) {
+	f, err := os.CreateTemp("", "nebula-console-*.ngql")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	if text != "" && !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	if _, err := f.WriteString(text); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	command := editor()
+	cmd := exec.Command(command[0], append(command[1:], f.Name())...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("editor %s failed, %s", strings.Join(command, " "), err.Error())
+	}
+	content, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+// JoinLines joins the lines edited into a line of the prompt, the blank lines and
+// the lines of comments are removed
+func JoinLines(text string) string {
+	var lines []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, "//") || strings.HasPrefix(line, "--") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, " ")
+}
+
+// pausableParser reads the input of go-prompt, which is paused while the editor is running,
+// otherwise go-prompt keeps reading the keys typed in the editor
+type pausableParser struct {
+	prompt.ConsoleParser
+	mu sync.Mutex
+}
+
+func (p *pausableParser) Read() ([]byte, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.ConsoleParser.Read()
+}
+
+// pause restores the terminal, and runs f
+func (p *pausableParser) pause(f func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ConsoleParser.TearDown()
+	defer p.ConsoleParser.Setup()
+	f()
+}
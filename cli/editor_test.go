@@ -0,0 +1,63 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package cli
+
+import (
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestJoinLines(t *testing.T) {
+	cases := []struct {
+		text string
+		want string
+	}{
+		{"", ""},
+		{"SHOW SPACES\n", "SHOW SPACES"},
+		// The blank lines and the lines of comments are removed
+		{"# find\nGO FROM 1 OVER e\n\n  -- the degree\n  YIELD e.degree\n// end\n", "GO FROM 1 OVER e YIELD e.degree"},
+	}
+	for _, c := range cases {
+		if got := JoinLines(c.text); got != c.want {
+			t.Errorf("JoinLines(%q) = %q, want %q", c.text, got, c.want)
+		}
+	}
+}
+
+func TestEditor(t *testing.T) {
+	t.Setenv("VISUAL", "")
+	t.Setenv("EDITOR", "code --wait")
+	if got, want := editor(), []string{"code", "--wait"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("editor = %q, want %q", got, want)
+	}
+	// VISUAL is preferred
+	t.Setenv("VISUAL", "nano")
+	if got, want := editor(), []string{"nano"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("editor = %q, want %q", got, want)
+	}
+}
+
+func TestEdit(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("the editor is a sed command")
+	}
+	// The editor changes the temp file in place, leaving the backup in the test directory
+	t.Setenv("TMPDIR", t.TempDir())
+	t.Setenv("VISUAL", "sed -i.bak s/SPACES/HOSTS/")
+	got, err := Edit("SHOW SPACES")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "SHOW HOSTS\n"; got != want {
+		t.Errorf("Edit = %q, want %q", got, want)
+	}
+
+	t.Setenv("VISUAL", "false")
+	if _, err := Edit("SHOW SPACES"); err == nil {
+		t.Errorf("Edit succeeded when the editor failed, want an error")
+	}
+}
@@ -0,0 +1,156 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package cli
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/c-bata/go-prompt"
+	"github.com/vesoft-inc/nebula-console/ngql"
+)
+
+// Style is the color of a kind of tokens
+type Style struct {
+	Color prompt.Color
+	Bold  bool
+}
+
+// Theme is the styles of the syntax highlighting, the other tokens are not colored
+type Theme struct {
+	Keyword   Style
+	String    Style
+	Number    Style
+	Parameter Style
+	Variable  Style
+	Comment   Style
+}
+
+// DefaultTheme uses the colors readable on both dark and light backgrounds
+var DefaultTheme = Theme{
+	Keyword:   Style{Color: prompt.Blue, Bold: true},
+	String:    Style{Color: prompt.DarkGreen},
+	Number:    Style{Color: prompt.Purple},
+	Parameter: Style{Color: prompt.Cyan},
+	Variable:  Style{Color: prompt.Brown},
+	Comment:   Style{Color: prompt.DarkGray},
+}
+
+var colorNames = map[string]prompt.Color{
+	"default": prompt.DefaultColor, "black": prompt.Black, "darkred": prompt.DarkRed,
+	"darkgreen": prompt.DarkGreen, "brown": prompt.Brown, "darkblue": prompt.DarkBlue,
+	"purple": prompt.Purple, "cyan": prompt.Cyan, "lightgray": prompt.LightGray,
+	"darkgray": prompt.DarkGray, "red": prompt.Red, "green": prompt.Green,
+	"yellow": prompt.Yellow, "blue": prompt.Blue, "fuchsia": prompt.Fuchsia,
+	"turquoise": prompt.Turquoise, "white": prompt.White,
+}
+
+// NoColor checks whether the colors are disabled by the environment variable NO_COLOR,
+// see https://no-color.org
+func NoColor() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
+
+// ParseTheme parses the styles changed from the default theme, i.e. "keyword=red+bold,comment=darkgray".
+// It returns nil if the highlighting is turned off by "off".
+func ParseTheme(spec string) (*Theme, error) {
+	theme := DefaultTheme
+	spec = strings.TrimSpace(spec)
+	if spec == "off" {
+		return nil, nil
+	}
+	if spec == "" || spec == "default" {
+		return &theme, nil
+	}
+	styles := map[string]*Style{
+		"keyword": &theme.Keyword, "string": &theme.String, "number": &theme.Number,
+		"parameter": &theme.Parameter, "variable": &theme.Variable, "comment": &theme.Comment,
+	}
+	for _, item := range strings.Split(spec, ",") {
+		kv := strings.SplitN(strings.TrimSpace(item), "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid style %q, expected <kind>=<color>[+bold]", item)
+		}
+		style, ok := styles[strings.ToLower(kv[0])]
+		if !ok {
+			return nil, fmt.Errorf("unknown kind %q, expected keyword, string, number, parameter, variable or comment", kv[0])
+		}
+		name := strings.ToLower(kv[1])
+		bold := strings.HasSuffix(name, "+bold")
+		color, ok := colorNames[strings.TrimSuffix(name, "+bold")]
+		if !ok {
+			return nil, fmt.Errorf("unknown color %q", kv[1])
+		}
+		*style = Style{Color: color, Bold: bold}
+	}
+	return &theme, nil
+}
+
+// style returns the style of the token, the nil means the default color
+func (t *Theme) style(token ngql.Token) *Style {
+	switch token.Kind {
+	case ngql.Word:
+		if ngql.IsKeyword(token.Text) {
+			return &t.Keyword
+		}
+	case ngql.String:
+		return &t.String
+	case ngql.Number:
+		return &t.Number
+	case ngql.Parameter:
+		return &t.Parameter
+	case ngql.Variable:
+		return &t.Variable
+	case ngql.Comment:
+		return &t.Comment
+	}
+	return nil
+}
+
+// promptWriter writes the prompt with its colors, which go-prompt would escape, and highlights
+// the input, which is the text written right after the prompt, both when the input is rendered
+// and when it's broken by Enter
+type promptWriter struct {
+	prompt.ConsoleWriter
+	// theme is nil if the highlighting is turned off
+	theme *Theme
+	// prefix is the prompt currently shown without the colors, go-prompt writes it
+	// and measures its width
+	prefix string
+	// coloredPrefix is the prompt written instead of prefix
+	coloredPrefix string
+	// afterPrefix is whether the next text written is the input
+	afterPrefix bool
+}
+
+func (w *promptWriter) WriteStr(data string) {
+	if !w.afterPrefix {
+		w.afterPrefix = data == w.prefix
+		if w.afterPrefix {
+			w.ConsoleWriter.WriteRawStr(w.coloredPrefix)
+		} else {
+			w.ConsoleWriter.WriteStr(data)
+		}
+		return
+	}
+	w.afterPrefix = false
+	if w.theme == nil {
+		w.ConsoleWriter.WriteStr(data)
+		return
+	}
+	line := strings.TrimSuffix(data, "\n")
+	for _, token := range ngql.Tokenize(line) {
+		if style := w.theme.style(token); style != nil {
+			w.ConsoleWriter.SetColor(style.Color, prompt.DefaultColor, style.Bold)
+			w.ConsoleWriter.WriteStr(token.Text)
+			w.ConsoleWriter.SetColor(prompt.DefaultColor, prompt.DefaultColor, false)
+		} else {
+			w.ConsoleWriter.WriteStr(token.Text)
+		}
+	}
+	w.ConsoleWriter.WriteStr(data[len(line):])
+}
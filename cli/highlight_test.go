@@ -0,0 +1,100 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package cli
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/c-bata/go-prompt"
+)
+
+func TestParseTheme(t *testing.T) {
+	custom := DefaultTheme
+	custom.Keyword = Style{Color: prompt.Red, Bold: true}
+	custom.Comment = Style{Color: prompt.DarkGray}
+	cases := []struct {
+		spec    string
+		want    *Theme
+		wantErr bool
+	}{
+		{"", &DefaultTheme, false},
+		{"default", &DefaultTheme, false},
+		{"off", nil, false},
+		{"keyword=red+bold, COMMENT=DarkGray", &custom, false},
+		{"keyword", nil, true},
+		{"operator=red", nil, true},
+		{"keyword=pink", nil, true},
+	}
+	for _, c := range cases {
+		got, err := ParseTheme(c.spec)
+		if (err != nil) != c.wantErr {
+			t.Errorf("ParseTheme(%q) error = %v, want error %t", c.spec, err, c.wantErr)
+			continue
+		}
+		if (got == nil) != (c.want == nil) || (got != nil && *got != *c.want) {
+			t.Errorf("ParseTheme(%q) = %+v, want %+v", c.spec, got, c.want)
+		}
+	}
+}
+
+// recordWriter records the text written and the colors set, the other methods are not used
+type recordWriter struct {
+	prompt.ConsoleWriter
+	b strings.Builder
+}
+
+func (w *recordWriter) WriteStr(data string) {
+	w.b.WriteString(data)
+}
+
+func (w *recordWriter) WriteRawStr(data string) {
+	w.b.WriteString("raw:" + data)
+}
+
+func (w *recordWriter) SetColor(fg, bg prompt.Color, bold bool) {
+	if fg == prompt.DefaultColor {
+		w.b.WriteString("]")
+		return
+	}
+	w.b.WriteString(fmt.Sprintf("[%d:", fg))
+}
+
+func TestPromptWriter(t *testing.T) {
+	theme := Theme{
+		Keyword:   Style{Color: prompt.Blue},
+		String:    Style{Color: prompt.Green},
+		Number:    Style{Color: prompt.Purple},
+		Parameter: Style{Color: prompt.Cyan},
+		Variable:  Style{Color: prompt.Brown},
+		Comment:   Style{Color: prompt.DarkGray},
+	}
+	cases := []struct {
+		theme *Theme
+		input string
+		want  string
+	}{
+		{&theme, `GO FROM "a" OVER e YIELD $p, 1 # c` + "\n",
+			fmt.Sprintf(`raw:\e[1m> [%[1]d:GO] [%[1]d:FROM] [%[2]d:"a"] [%[1]d:OVER] e [%[1]d:YIELD] [%[3]d:$p], [%[4]d:1] [%[5]d:# c]`+"\n",
+				prompt.Blue, prompt.Green, prompt.Cyan, prompt.Purple, prompt.DarkGray)},
+		// The escaped quote doesn't end the string
+		{&theme, `YIELD 'it\'s'`, fmt.Sprintf(`raw:\e[1m> [%[1]d:YIELD] [%[2]d:'it\'s']`, prompt.Blue, prompt.Green)},
+		// The input is not highlighted without a theme
+		{nil, `GO FROM "a"`, `raw:\e[1m> GO FROM "a"`},
+	}
+	for _, c := range cases {
+		rw := &recordWriter{}
+		w := &promptWriter{ConsoleWriter: rw, theme: c.theme, prefix: "> ", coloredPrefix: `\e[1m> `}
+		// The text before the prompt is written as it is
+		w.WriteStr("\n")
+		w.WriteStr("> ")
+		w.WriteStr(c.input)
+		if got := strings.TrimPrefix(rw.b.String(), "\n"); got != c.want {
+			t.Errorf("highlighted %q = %q, want %q", c.input, got, c.want)
+		}
+	}
+}
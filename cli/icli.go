@@ -7,8 +7,9 @@ package cli
 
 import (
 	"io"
-	"log"
-	"os"
+	"strings"
+
+	"github.com/vesoft-inc/nebula-console/ngql"
 )
 
 // interactive
@@ -17,28 +18,26 @@ type iCli struct {
 	terminal Terminal
 }
 
-func NewiCli(historyFile, user string, enableGoPrompt bool) Cli {
+// NewiCli creates the interactive console, the history is recalled from the oldest statement.
+// The input is highlighted by the theme with go-prompt, liner doesn't support it.
+// The prompt is rendered by the template, the default one is used if it's nil.
+func NewiCli(history []string, user string, enableGoPrompt bool, theme *Theme, prompt *Prompt) Cli {
 	var t Terminal
 	if enableGoPrompt {
-		t = NewGoPromptTerminal()
+		t = NewGoPromptTerminal(theme)
 	} else {
 		t = NewLinerTerminal()
 	}
 
-	f, err := os.OpenFile(historyFile, os.O_RDONLY|os.O_CREATE, 0666)
-	if err != nil {
-		log.Fatalf("Open or create history file %s failed, %s", historyFile, err.Error())
-	}
-	defer f.Close()
-	t.ReadHistory(f)
+	t.ReadHistory(strings.NewReader(strings.Join(history, "\n")))
 
 	return &iCli{
 		status: status{
-			historyFile:          historyFile,
 			user:                 user,
 			space:                "(none)",
 			promptLen:            -1,
 			promptColor:          -1,
+			prompt:               prompt,
 			playingData:          false,
 			line:                 "",
 			joinedByTripleQuotes: false,
@@ -61,7 +60,8 @@ func (l *iCli) ReadLine() (string, bool, error) {
 				continue
 			}
 			if len(l.status.line) > 0 {
-				l.terminal.AppendHistory(l.status.line)
+				// The credentials are masked, i.e. CREATE USER bob WITH PASSWORD '***'
+				l.terminal.AppendHistory(ngql.Redact(l.status.line))
 			}
 			return l.status.line, false, nil
 		} else if err == ErrPromptAborted {
@@ -108,12 +108,11 @@ func (l iCli) IsPlayingData() bool {
 	return l.playingData
 }
 
+func (l *iCli) Position() (int, int) {
+	return 0, 0
+}
+
+// Close closes the terminal, the history is stored by the caller with the metadata
 func (l *iCli) Close() {
-	defer l.terminal.Close()
-	f, err := os.OpenFile(l.status.historyFile, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0666)
-	if err != nil {
-		log.Fatalf("Open or create history file %s failed, %s", l.status.historyFile, err.Error())
-	}
-	defer f.Close()
-	l.terminal.WriteHistory(f)
+	l.terminal.Close()
 }
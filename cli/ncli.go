@@ -9,6 +9,9 @@ import (
 	"bufio"
 	"fmt"
 	"io"
+	"strings"
+
+	"github.com/vesoft-inc/nebula-console/ngql"
 )
 
 type Cleanup func()
@@ -19,6 +22,19 @@ type nCli struct {
 	io      *bufio.Reader
 	output  bool
 	cleanup Cleanup
+
+	// lines read, the line where the last statement starts and the statements read
+	lineNo     int
+	stmtLine   int
+	statements int
+
+	// the lines of the statement being joined, which are echoed when it's complete
+	echo []echoLine
+}
+
+type echoLine struct {
+	prompt string
+	input  string
 }
 
 func NewnCli(i io.Reader, output bool, user string, cleanup Cleanup) Cli {
@@ -61,17 +77,25 @@ func (l *nCli) ReadLine() (string, bool, error) {
 	for {
 		input, err := readln(l.io)
 		if err == nil {
+			l.lineNo++
+			if !l.status.joinedByTripleQuotes && !l.status.joinedByBackSlash {
+				l.stmtLine = l.lineNo
+			}
 			if l.output {
-				fmt.Print(l.status.nebulaPrompt())
-				// not record input to historyFile now
-				fmt.Println(input)
+				l.echo = append(l.echo, echoLine{prompt: l.status.nebulaPrompt(), input: input})
 			}
 			l.status.checkJoined(input)
 			if l.status.joinedByTripleQuotes || l.status.joinedByBackSlash {
 				continue
 			}
+			l.printEcho(l.status.line)
+			if len(l.status.line) > 0 {
+				l.statements++
+			}
 			return l.status.line, false, nil
 		} else if err == io.EOF {
+			// the statement left unfinished
+			l.printEcho(l.status.line)
 			return "", true, nil
 		} else {
 			return "", false, err
@@ -79,6 +103,34 @@ func (l *nCli) ReadLine() (string, bool, error) {
 	}
 }
 
+// printEcho prints the lines of the statement read. The credentials are masked by the joined
+// statement, since they may be on the lines continued by \ or """, and the statement is printed
+// in one line if a credential is split across the lines.
+func (l *nCli) printEcho(stmt string) {
+	lines := l.echo
+	l.echo = nil
+	if len(lines) == 0 {
+		return
+	}
+	secrets := ngql.Secrets(stmt)
+	for _, secret := range secrets {
+		found := false
+		for _, line := range lines {
+			found = found || strings.Contains(line.input, secret)
+		}
+		if !found {
+			fmt.Print(lines[0].prompt)
+			fmt.Println(ngql.Redact(stmt))
+			return
+		}
+	}
+	for _, line := range lines {
+		fmt.Print(line.prompt)
+		// not record input to historyFile now
+		fmt.Println(ngql.RedactMessage(ngql.Redact(line.input), secrets))
+	}
+}
+
 func (l *nCli) Interactive() bool {
 	return false
 }
@@ -111,6 +163,24 @@ func (l nCli) IsPlayingData() bool {
 	return l.playingData
 }
 
+func (l *nCli) Position() (int, int) {
+	return l.statements, l.stmtLine
+}
+
+// CountStatements returns the number of the statements in the script read by nCli
+func CountStatements(r io.Reader) (int, error) {
+	c := NewnCli(r, false, "", nil).(*nCli)
+	for {
+		_, exit, err := c.ReadLine()
+		if err != nil {
+			return 0, err
+		}
+		if exit {
+			return c.statements, nil
+		}
+	}
+}
+
 func (l *nCli) Close() {
 	if l.cleanup != nil {
 		l.cleanup()
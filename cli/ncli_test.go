@@ -0,0 +1,65 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package cli
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// readAll reads the statements of the script by nCli, and returns them with the echo printed
+func readAll(t *testing.T, script string) ([]string, string) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	var statements []string
+	c := NewnCli(strings.NewReader(script), true, "root", nil)
+	for {
+		line, exit, err := c.ReadLine()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if exit {
+			break
+		}
+		statements = append(statements, line)
+	}
+	w.Close()
+	echo, _ := io.ReadAll(r)
+	return statements, string(echo)
+}
+
+func TestReadLineEcho(t *testing.T) {
+	cases := []struct {
+		script     string
+		statements []string
+	}{
+		{"CREATE USER u WITH PASSWORD 'secret'\nSHOW SPACES\n", []string{"CREATE USER u WITH PASSWORD 'secret'", "SHOW SPACES"}},
+		// The password on the line continued by \ or """
+		{"CREATE USER u WITH PASSWORD \\\n\"secret\"\n", []string{"CREATE USER u WITH PASSWORD \"secret\""}},
+		{"\"\"\"\nALTER USER u WITH PASSWORD\n'secret'\n\"\"\"\n", []string{"ALTER USER u WITH PASSWORD 'secret' "}},
+		// The password split across the lines
+		{"CREATE USER u WITH PASSWORD \"sec\\\nret\"\n", []string{"CREATE USER u WITH PASSWORD \"secret\""}},
+		// The statement left unfinished is still echoed
+		{"CREATE USER u WITH PASSWORD 'secret' \\\n", nil},
+	}
+	for _, c := range cases {
+		statements, echo := readAll(t, c.script)
+		if strings.Join(statements, "\n") != strings.Join(c.statements, "\n") {
+			t.Errorf("statements of %q = %q, want %q", c.script, statements, c.statements)
+		}
+		if strings.Contains(echo, "secret") || !strings.Contains(echo, "***") {
+			t.Errorf("echo of %q = %q, want the password masked", c.script, echo)
+		}
+	}
+}
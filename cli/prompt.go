@@ -0,0 +1,120 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package cli
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// DefaultPrompt is the template of the prompt by default, i.e. (root@nebula) [basketballplayer]>
+const DefaultPrompt = "({user}@nebula) [{space}]> "
+
+var placeholderRegexp = regexp.MustCompile(`\{([a-z]+)\}`)
+
+var colorRegexp = regexp.MustCompile("\033\\[[0-9;]*m")
+
+// The placeholders of the colors, which are ANSI escape sequences
+var promptColors = map[string]string{
+	"red": "\033[31m", "green": "\033[32m", "yellow": "\033[33m", "blue": "\033[34m",
+	"magenta": "\033[35m", "cyan": "\033[36m", "white": "\033[37m",
+	"bgred": "\033[41m", "bold": "\033[1m", "reset": "\033[0m",
+}
+
+// The placeholders of the status
+var promptFields = map[string]bool{
+	"user": true, "space": true, "host": true, "profile": true,
+	"version": true, "time": true, "latency": true, "mode": true,
+}
+
+// Prompt is the template of the prompt, and the status of the connection shown by the placeholders
+type Prompt struct {
+	template string
+	// Host is the address of graphd, i.e. 127.0.0.1:9669
+	Host string
+	// Profile is the name of the connection profile
+	Profile string
+	// Version is the version of graphd
+	Version string
+	// ReadOnly is whether the statements which don't only read are rejected
+	ReadOnly bool
+	// Latency is the server latency of the last statement
+	Latency time.Duration
+	// Color is whether the color placeholders are rendered, they are removed otherwise
+	Color bool
+}
+
+// NewPrompt parses the template, i.e. "{red}PROD{reset} {user}@{host} [{space}]> "
+func NewPrompt(template string) (*Prompt, error) {
+	for _, m := range placeholderRegexp.FindAllStringSubmatch(template, -1) {
+		if _, ok := promptColors[m[1]]; !ok && !promptFields[m[1]] {
+			return nil, fmt.Errorf("unknown placeholder %s", m[0])
+		}
+	}
+	return &Prompt{template: template, Color: true}, nil
+}
+
+// Uses checks whether the template has the placeholder, i.e. Uses("version")
+func (p *Prompt) Uses(name string) bool {
+	return strings.Contains(p.template, "{"+name+"}")
+}
+
+// Render replaces the placeholders of the template, the colors are reset at the end
+func (p *Prompt) Render(user, space string) string {
+	colored := false
+	prompt := placeholderRegexp.ReplaceAllStringFunc(p.template, func(placeholder string) string {
+		name := placeholder[1 : len(placeholder)-1]
+		if color, ok := promptColors[name]; ok {
+			if !p.Color {
+				return ""
+			}
+			colored = true
+			return color
+		}
+		switch name {
+		case "user":
+			return user
+		case "space":
+			return space
+		case "host":
+			return p.Host
+		case "profile":
+			return p.Profile
+		case "version":
+			return p.Version
+		case "time":
+			return time.Now().Format("15:04:05")
+		case "latency":
+			if p.Latency == 0 {
+				return "-"
+			}
+			return p.Latency.String()
+		case "mode":
+			if p.ReadOnly {
+				return "RO"
+			}
+			return "RW"
+		}
+		return placeholder
+	})
+	if colored {
+		prompt += promptColors["reset"]
+	}
+	return prompt
+}
+
+// stripColors removes the ANSI colors of the prompt
+func stripColors(s string) string {
+	return colorRegexp.ReplaceAllString(s, "")
+}
+
+// promptWidth returns the number of the characters shown
+func promptWidth(s string) int {
+	return utf8.RuneCountInString(stripColors(s))
+}
@@ -0,0 +1,68 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package cli
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPromptRender(t *testing.T) {
+	cases := []struct {
+		template string
+		readOnly bool
+		latency  time.Duration
+		color    bool
+		want     string
+	}{
+		{DefaultPrompt, false, 0, true, "(root@nebula) [nba]> "},
+		{"{user}@{host}/{profile} {version} {mode} {latency}> ", true, 0, true, "root@127.0.0.1:9669/prod 3.6.0 RO -> "},
+		{"{mode} {latency}> ", false, 1500 * time.Microsecond, true, "RW 1.5ms> "},
+		// The colors are reset at the end, and they are removed without colors
+		{"{red}PROD{reset} [{space}]> ", false, 0, true, "\033[31mPROD\033[0m [nba]> \033[0m"},
+		{"{red}PROD{reset} [{space}]> ", false, 0, false, "PROD [nba]> "},
+	}
+	for _, c := range cases {
+		p, err := NewPrompt(c.template)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p.Host, p.Profile, p.Version = "127.0.0.1:9669", "prod", "3.6.0"
+		p.ReadOnly, p.Latency, p.Color = c.readOnly, c.latency, c.color
+		if got := p.Render("root", "nba"); got != c.want {
+			t.Errorf("Render(%q) = %q, want %q", c.template, got, c.want)
+		}
+	}
+}
+
+func TestNewPrompt(t *testing.T) {
+	if _, err := NewPrompt("{user} {unknown}> "); err == nil {
+		t.Errorf("NewPrompt with an unknown placeholder succeeded, want an error")
+	}
+	p, err := NewPrompt("{time} [{space}]> ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.Uses("time") || p.Uses("version") {
+		t.Errorf("Uses(time) = %t, Uses(version) = %t, want true and false", p.Uses("time"), p.Uses("version"))
+	}
+}
+
+func TestPromptWidth(t *testing.T) {
+	cases := []struct {
+		prompt string
+		want   int
+	}{
+		{"(root@nebula) [nba]> ", 21},
+		{"\033[31mPROD\033[0m> \033[0m", 6},
+		{"[空间]> ", 6},
+	}
+	for _, c := range cases {
+		if got := promptWidth(c.prompt); got != c.want {
+			t.Errorf("promptWidth(%q) = %d, want %d", c.prompt, got, c.want)
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"unicode/utf8"
 
 	"github.com/c-bata/go-prompt"
@@ -68,8 +69,9 @@ func (l *LinerTerminal) Close() {
 	l.state.Close()
 }
 
+// Prompt shows the prompt without the colors, which liner counts in the width of the prompt
 func (l *LinerTerminal) Prompt(p string) (string, error) {
-	s, err := l.state.Prompt(p)
+	s, err := l.state.Prompt(stripColors(p))
 	if err == liner.ErrPromptAborted {
 		return "", ErrPromptAborted
 	}
@@ -78,6 +80,11 @@ func (l *LinerTerminal) Prompt(p string) (string, error) {
 
 type GoPromptTerminal struct {
 	prompt *prompt.Prompt
+	parser *pausableParser
+	writer *promptWriter
+	// go-prompt records every line entered, which is replaced by the history
+	// of the complete statements
+	history []string
 }
 
 func goPromptSuggest(d prompt.Document) []prompt.Suggest {
@@ -90,14 +97,43 @@ func goPromptSuggest(d prompt.Document) []prompt.Suggest {
 	return suggests
 }
 
-func NewGoPromptTerminal() Terminal {
+// NewGoPromptTerminal creates the terminal of go-prompt, the input is highlighted by the theme if it's not nil
+func NewGoPromptTerminal(theme *Theme) Terminal {
 	executor := func(s string) {
 	}
-	p := prompt.New(executor, goPromptSuggest)
-	t := &GoPromptTerminal{prompt: p}
+	t := &GoPromptTerminal{parser: &pausableParser{ConsoleParser: prompt.NewStandardInputParser()}}
+	t.writer = &promptWriter{ConsoleWriter: prompt.NewStdoutWriter(), theme: theme}
+	t.prompt = prompt.New(executor, goPromptSuggest,
+		prompt.OptionParser(t.parser),
+		prompt.OptionWriter(t.writer),
+		prompt.OptionAddKeyBind(prompt.KeyBind{Key: prompt.ControlX, Fn: t.edit}))
 	return t
 }
 
+// edit opens the input, or the previous statement if the input is empty, in the editor by Ctrl-X,
+// and the text edited is placed into the prompt
+func (g *GoPromptTerminal) edit(buf *prompt.Buffer) {
+	text := buf.Text()
+	if strings.TrimSpace(text) == "" && len(g.history) > 0 {
+		text = g.history[len(g.history)-1]
+	}
+	var (
+		edited string
+		err    error
+	)
+	g.parser.pause(func() {
+		edited, err = Edit(text)
+	})
+	if err != nil {
+		fmt.Println()
+		fmt.Println("Error: " + err.Error())
+		return
+	}
+	buf.CursorRight(len([]rune(buf.Document().TextAfterCursor())))
+	buf.DeleteBeforeCursor(len([]rune(buf.Text())))
+	buf.InsertText(JoinLines(edited), false, true)
+}
+
 // TODO should enhance go-prompt
 func (g *GoPromptTerminal) ReadHistory(r io.Reader) error {
 	h := make([]string, 0)
@@ -118,15 +154,25 @@ func (g *GoPromptTerminal) ReadHistory(r io.Reader) error {
 		}
 		h = append(h, string(line))
 	}
-	return prompt.OptionHistory(h)(g.prompt)
+	g.history = h
+	return prompt.OptionHistory(append([]string{}, g.history...))(g.prompt)
 }
 
 func (g *GoPromptTerminal) AppendHistory(item string) {
-	return
+	// The same as liner, the consecutive duplicates are ignored
+	if n := len(g.history); n > 0 && g.history[n-1] == item {
+		return
+	}
+	g.history = append(g.history, item)
+	prompt.OptionHistory(append([]string{}, g.history...))(g.prompt)
 }
 
-// TODO go-prompt has no interface to write history.
 func (g *GoPromptTerminal) WriteHistory(w io.Writer) error {
+	for _, item := range g.history {
+		if _, err := fmt.Fprintln(w, item); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -135,7 +181,9 @@ func (g *GoPromptTerminal) Close() {
 }
 
 func (g *GoPromptTerminal) Prompt(p string) (string, error) {
-	prompt.OptionPrefix(p)(g.prompt)
+	// go-prompt measures the prompt without the colors, which are written by the writer
+	g.writer.prefix, g.writer.coloredPrefix = stripColors(p), p
+	prompt.OptionPrefix(g.writer.prefix)(g.prompt)
 	s := g.prompt.Input()
 	return s, nil
 }
@@ -7,37 +7,9 @@ package completer
 
 import (
 	"strings"
-)
 
-// all keywords
-var keywords = []string{
-	"GO", "AS", "TO", "OR", "AND", "XOR", "USE", "SET", "FROM",
-	"WHERE", "MATCH", "INSERT", "YIELD", "RETURN", "DESCRIBE",
-	"DESC", "VERTEX", "EDGE", "EDGES", "UPDATE", "UPSERT",
-	"WHEN", "DELETE", "FIND", "LOOKUP", "ALTER", "STEPS", "OVER",
-	"UPTO", "REVERSELY", "INDEX", "INDEXES", "REBUILD", "BOOL",
-	"INT8", "INT16", "INT32", "INT64", "INT", "FLOAT", "DOUBLE",
-	"STRING", "FIXED_STRING", "TIMESTAMP", "DATE", "DATETIME",
-	"TAG", "TAGS", "UNION", "INTERSECT", "MINUS", "NO", "OVERWRITE",
-	"SHOW", "ADD", "CREATE", "DROP", "REMOVE", "IF", "NOT", "EXISTS",
-	"WITH", "CHANGE", "GRANT", "REVOKE", "ON", "BY", "IN", "DOWNLOAD",
-	"GET", "OF", "ORDER", "INGEST", "COMPACT", "FLUSH", "SUBMIT",
-	"ASC", "DISTINCT", "FETCH", "PROP", "BALANCE", "STOP", "LIMIT",
-	"OFFSET", "IS", "NULL", "RECOVER", "EXPLAIN", "PROFILE", "FORMAT",
-	"CASE", "MATCH", "UNWIND", "SKIP", "SIGN",
-	"HOSTS", "SPACE", "SPACES", "VALUES", "USER", "USERS", "PASSWORD",
-	"ROLE", "ROLES", "GOD", "ADMIN", "DBA", "GUEST", "GROUP", "PARTITION_NUM",
-	"REPLICA_FACTOR", "VID_SIZE", "CHARSET", "COLLATE", "COLLATION", "ALL",
-	"LEADER", "UUID", "DATA", "SNAPSHOT", "SNAPSHOTS", "OFFLINE", "ACCOUNT",
-	"JOBS", "JOB", "COUNT", "COUNT_DISTINCT", "SUM", "AVG", "MAX", "MIN",
-	"STD", "BIT_AND", "BIT_OR", "BIT_XOR", "PATH", "BIDIRECT", "STATUS", "FORCE",
-	"PART", "PARTS", "DEFAULT", "HDFS", "CONFIGS", "TTL_DURATION", "TTL_COL",
-	"GRAPH", "META", "STORAGE", "SHORTEST", "OUT", "BOTH", "SUBGRAPH", "CONTAINS",
-	"TRUE", "FALSE", "THEN", "ELSE", "END", "STARTS", "ENDS", "WITH",
-	"<-", "->", "_id", "_type", "_src", "_dst", "_rank", "$$", "$^", "$-",
-	/* ".", ",", ":", ";", "@", "+", "-", "*", "/", "%", "!", "^", "<", "<=",
-	   ">", ">=", "==", "!=", "||", "&&", "|", "=", "(", ")", "[", "]" */
-}
+	"github.com/vesoft-inc/nebula-console/ngql"
+)
 
 var subCmds = map[string][]string{
 	/* SHOW */
@@ -108,20 +80,29 @@ func NewCompleter(line string, pos int) (head string, completions []string, tail
 	if len(line) < 1 {
 		return
 	}
-	words := strings.Fields(line[:pos])
-	if len(words) < 1 {
+	tokens := ngql.Tokenize(line[:pos])
+	if len(tokens) < 1 {
 		return
 	}
-	lastWord := strings.ToUpper(words[len(words)-1])
-	h := strings.LastIndex(line[:pos], " ")
-	head = line[:h+1]
+	last := tokens[len(tokens)-1]
 	tail = line[pos:]
-	if line[pos-1] == ' ' { // find sub cmd
-		if subs, ok := subCmds[lastWord]; ok {
+	switch last.Kind {
+	case ngql.Space: // find sub cmd
+		head = line[:pos]
+		words := ngql.Significant(tokens)
+		if len(words) < 1 {
+			return
+		}
+		if subs, ok := subCmds[strings.ToUpper(words[len(words)-1].Text)]; ok {
 			completions = append(completions, subs...)
 		}
-	} else {
-		for _, k := range keywords {
+	case ngql.String, ngql.QuotedName, ngql.Comment, ngql.Number:
+		// Nothing to complete in the literals and comments
+		return
+	default:
+		head = line[:last.Pos]
+		lastWord := strings.ToUpper(last.Text)
+		for _, k := range ngql.Keywords {
 			if strings.HasPrefix(k, lastWord) {
 				completions = append(completions, k)
 			}
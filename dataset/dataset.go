@@ -0,0 +1,169 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package dataset
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/vesoft-inc/nebula-console/box"
+)
+
+// ManifestFile is the name of the manifest in a dataset directory
+const ManifestFile = "datasets.json"
+
+// EnvDatasets is the environment variable of the user dataset directories,
+// which are separated by the path list separator, i.e. ":" on Linux
+const EnvDatasets = "NEBULA_CONSOLE_DATASETS"
+
+// Dataset is an nGQL script loaded by :play, the counts are verified after loading
+type Dataset struct {
+	Name        string `json:"name"`
+	File        string `json:"file"`
+	Description string `json:"description"`
+	Space       string `json:"space"`
+	VidType     string `json:"vid_type"`
+	Vertices    int64  `json:"vertices"`
+	Edges       int64  `json:"edges"`
+
+	// the directory of the dataset, or empty for the embedded ones
+	dir string
+}
+
+// Source returns where the dataset is found
+func (d *Dataset) Source() string {
+	if d.dir == "" {
+		return "embedded"
+	}
+	return filepath.Join(d.dir, d.File)
+}
+
+// Open opens the script of the dataset
+func (d *Dataset) Open() (io.ReadCloser, error) {
+	if d.dir == "" {
+		content := box.Get("/" + d.File)
+		if content == nil {
+			return nil, fmt.Errorf("file %s not existed in embed box", d.File)
+		}
+		return io.NopCloser(bytes.NewReader(content)), nil
+	}
+	return os.Open(filepath.Join(d.dir, d.File))
+}
+
+type manifest struct {
+	Datasets []*Dataset `json:"datasets"`
+}
+
+// Registry is the datasets found in the user directories, ./data and the embedded box.
+// A dataset hides the ones with the same name found later.
+type Registry struct {
+	datasets []*Dataset
+	index    map[string]*Dataset
+}
+
+func (r *Registry) add(d *Dataset) {
+	if _, ok := r.index[d.Name]; ok {
+		return
+	}
+	r.index[d.Name] = d
+	r.datasets = append(r.datasets, d)
+}
+
+// addFiles registers the datasets in the manifest, and the .ngql files not in the manifest
+// by their names without metadata
+func (r *Registry) addFiles(dir string, manifestContent []byte, files []string) error {
+	listed := make(map[string]bool)
+	if manifestContent != nil {
+		var m manifest
+		if err := json.Unmarshal(manifestContent, &m); err != nil {
+			return fmt.Errorf("parse manifest of %s failed, %s", dir, err.Error())
+		}
+		for _, d := range m.Datasets {
+			if d.Name == "" || d.File == "" {
+				return fmt.Errorf("dataset without name or file in the manifest of %s", dir)
+			}
+			d.dir = dir
+			listed[d.File] = true
+			r.add(d)
+		}
+	}
+	for _, file := range files {
+		if listed[file] || !strings.HasSuffix(file, ".ngql") {
+			continue
+		}
+		r.add(&Dataset{Name: strings.TrimSuffix(file, ".ngql"), File: file, dir: dir})
+	}
+	return nil
+}
+
+func (r *Registry) addDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			files = append(files, entry.Name())
+		}
+	}
+	content, err := os.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return r.addFiles(dir, content, files)
+}
+
+// Load finds the datasets in the directories of NEBULA_CONSOLE_DATASETS, ./data and then the embedded box
+func Load() (*Registry, error) {
+	r := &Registry{index: make(map[string]*Dataset)}
+	var dirs []string
+	if env := os.Getenv(EnvDatasets); env != "" {
+		dirs = filepath.SplitList(env)
+	}
+	dirs = append(dirs, "./data")
+	for _, dir := range dirs {
+		if err := r.addDir(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	var files []string
+	for _, file := range box.List() {
+		if path.Dir(file) == "/" {
+			files = append(files, strings.TrimPrefix(file, "/"))
+		}
+	}
+	if err := r.addFiles("", box.Get("/"+ManifestFile), files); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// List returns the datasets ordered by name
+func (r *Registry) List() []*Dataset {
+	datasets := append([]*Dataset{}, r.datasets...)
+	sort.Slice(datasets, func(i, j int) bool {
+		return datasets[i].Name < datasets[j].Name
+	})
+	return datasets
+}
+
+// Get returns the dataset by name
+func (r *Registry) Get(name string) (*Dataset, bool) {
+	d, ok := r.index[name]
+	return d, ok
+}
@@ -0,0 +1,141 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package dataset
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/vesoft-inc/nebula-console/box"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	ttypes "github.com/vesoft-inc/nebula-go/v3/nebula"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+)
+
+func writeFile(t *testing.T, dir, name, content string) {
+	if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	user, other := t.TempDir(), t.TempDir()
+	writeFile(t, user, ManifestFile, `{"datasets": [{"name": "nba", "file": "nba.ngql", "space": "nba", "vertices": 2}]}`)
+	writeFile(t, user, "nba.ngql", "CREATE SPACE nba;")
+	// The scripts not in the manifest are datasets without metadata, the other files are ignored
+	writeFile(t, user, "extra.ngql", "")
+	writeFile(t, user, "README.md", "")
+	// The dataset hidden by the one with the same name found earlier
+	writeFile(t, other, "nba.ngql", "")
+	writeFile(t, other, "other.ngql", "")
+	box.Add("/"+ManifestFile, []byte(`{"datasets": [{"name": "basketballplayer", "file": "basketballplayer.ngql"}]}`))
+	box.Add("/basketballplayer.ngql", []byte("CREATE SPACE basketballplayer;"))
+	t.Setenv(EnvDatasets, user+string(filepath.ListSeparator)+other+string(filepath.ListSeparator)+filepath.Join(user, "missing"))
+
+	r, err := Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, d := range r.List() {
+		names = append(names, d.Name)
+	}
+	if want := []string{"basketballplayer", "extra", "nba", "other"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("List = %v, want %v", names, want)
+	}
+
+	cases := []struct {
+		name    string
+		source  string
+		content string
+	}{
+		{"nba", filepath.Join(user, "nba.ngql"), "CREATE SPACE nba;"},
+		{"basketballplayer", "embedded", "CREATE SPACE basketballplayer;"},
+	}
+	for _, c := range cases {
+		d, ok := r.Get(c.name)
+		if !ok {
+			t.Fatalf("dataset %s not found", c.name)
+		}
+		if d.Source() != c.source {
+			t.Errorf("Source of %s = %s, want %s", c.name, d.Source(), c.source)
+		}
+		f, err := d.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := io.ReadAll(f)
+		f.Close()
+		if string(content) != c.content {
+			t.Errorf("content of %s = %q, want %q", c.name, content, c.content)
+		}
+	}
+	if d, _ := r.Get("nba"); d.Space != "nba" || d.Vertices != 2 {
+		t.Errorf("metadata of nba = %+v", d)
+	}
+	if _, ok := r.Get("missing"); ok {
+		t.Errorf("Get(missing) found a dataset")
+	}
+}
+
+func TestManifestErrors(t *testing.T) {
+	for _, manifest := range []string{
+		`{"datasets": [`,
+		`{"datasets": [{"name": "nba"}]}`,
+	} {
+		dir := t.TempDir()
+		writeFile(t, dir, ManifestFile, manifest)
+		t.Setenv(EnvDatasets, dir)
+		if _, err := Load(); err == nil {
+			t.Errorf("Load of the manifest %s succeeded, want an error", manifest)
+		}
+	}
+}
+
+func TestSpaceCounts(t *testing.T) {
+	row := func(typ, name string, count int64) *ttypes.Row {
+		return &ttypes.Row{Values: []*ttypes.Value{{SVal: []byte(typ)}, {SVal: []byte(name)}, {IVal: &count}}}
+	}
+	res, err := nebula.GenResultSet(&graph.ExecutionResponse{ErrorCode: ttypes.ErrorCode_SUCCEEDED, Data: &ttypes.DataSet{
+		ColumnNames: [][]byte{[]byte("Type"), []byte("Name"), []byte("Count")},
+		Rows: []*ttypes.Row{
+			row("Tag", "player", 51),
+			row("Edge", "follow", 81),
+			row("Space", "vertices", 81),
+			row("Space", "edges", 233),
+		},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	vertices, edges, err := spaceCounts(res)
+	if err != nil || vertices != 81 || edges != 233 {
+		t.Errorf("spaceCounts = %d, %d, %v, want 81 and 233", vertices, edges, err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	cases := []struct {
+		a, b string
+		want bool
+	}{
+		{"FIXED_STRING(32)", "fixed_string( 32 )", true},
+		{"INT64", "int64", true},
+		{"FIXED_STRING(32)", "FIXED_STRING(16)", false},
+	}
+	for _, c := range cases {
+		if got := sameVidType(c.a, c.b); got != c.want {
+			t.Errorf("sameVidType(%s, %s) = %t, want %t", c.a, c.b, got, c.want)
+		}
+	}
+	// Nothing is verified without the space in the manifest
+	if msg, err := (&Dataset{Name: "extra"}).Verify(nil); msg != "" || err != nil {
+		t.Errorf("Verify without the space = %q, %v", msg, err)
+	}
+}
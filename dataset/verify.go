@@ -0,0 +1,134 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package dataset
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/vesoft-inc/nebula-console/importer"
+	"github.com/vesoft-inc/nebula-console/waiter"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+)
+
+// The longest time to wait for the stats job
+const statsTimeout = 60 * time.Second
+
+func execute(session *nebula.Session, stmt string) (*nebula.ResultSet, error) {
+	res, err := session.Execute(stmt)
+	if err != nil {
+		return nil, err
+	}
+	if !res.IsSucceed() {
+		return nil, fmt.Errorf("an error occurred when executing: %s, [ERROR (%d)]: %s", stmt, res.GetErrorCode(), res.GetErrorMsg())
+	}
+	return res, nil
+}
+
+func cell(res *nebula.ResultSet, row, col int) (*nebula.ValueWrapper, error) {
+	record, err := res.GetRowValuesByIndex(row)
+	if err != nil {
+		return nil, err
+	}
+	return record.GetValueByIndex(col)
+}
+
+func cellString(res *nebula.ResultSet, row, col int) string {
+	val, err := cell(res, row, col)
+	if err != nil {
+		return ""
+	}
+	if s, err := val.AsString(); err == nil {
+		return s
+	}
+	return val.String()
+}
+
+// stats submits a stats job, waits for it, and returns the vertices and edges of SHOW STATS
+func stats(session *nebula.Session) (int64, int64, error) {
+	res, err := execute(session, "SUBMIT JOB STATS")
+	if err != nil {
+		return 0, 0, err
+	}
+	val, err := cell(res, 0, 0)
+	if err != nil {
+		return 0, 0, err
+	}
+	job, err := val.AsInt()
+	if err != nil {
+		return 0, 0, err
+	}
+	if err := waiter.WaitJob(session, job, statsTimeout); err != nil {
+		return 0, 0, err
+	}
+
+	res, err = execute(session, "SHOW STATS")
+	if err != nil {
+		return 0, 0, err
+	}
+	return spaceCounts(res)
+}
+
+// spaceCounts returns the vertices and edges of the space in the result of SHOW STATS,
+// whose columns are Type, Name and Count, i.e. Space, vertices, 81
+func spaceCounts(res *nebula.ResultSet) (int64, int64, error) {
+	var vertices, edges int64
+	for i := 0; i < res.GetRowSize(); i++ {
+		if cellString(res, i, 0) != "Space" {
+			continue
+		}
+		val, err := cell(res, i, 2)
+		if err != nil {
+			return 0, 0, err
+		}
+		count, _ := val.AsInt()
+		switch cellString(res, i, 1) {
+		case "vertices":
+			vertices = count
+		case "edges":
+			edges = count
+		}
+	}
+	return vertices, edges, nil
+}
+
+// sameVidType compares the vid types ignoring the case and the spaces, i.e. FIXED_STRING(32) and fixed_string( 32 )
+func sameVidType(a, b string) bool {
+	return strings.EqualFold(strings.ReplaceAll(a, " ", ""), strings.ReplaceAll(b, " ", ""))
+}
+
+// Verify checks the vid type and the counts of the space loaded by the dataset,
+// nothing is checked if the manifest doesn't specify them
+func (d *Dataset) Verify(session *nebula.Session) (string, error) {
+	if d.Space == "" {
+		return "", nil
+	}
+	if d.VidType != "" {
+		vidType, err := importer.DescribeVidType(session, d.Space)
+		if err != nil {
+			return "", err
+		}
+		if !sameVidType(vidType, d.VidType) {
+			return "", fmt.Errorf("vid type of space %s is %s, but %s is required by dataset %s", d.Space, vidType, d.VidType, d.Name)
+		}
+	}
+	if d.Vertices == 0 && d.Edges == 0 {
+		return "", nil
+	}
+	if _, err := execute(session, fmt.Sprintf("USE `%s`", d.Space)); err != nil {
+		return "", err
+	}
+	vertices, edges, err := stats(session)
+	if err != nil {
+		return "", err
+	}
+	if vertices != d.Vertices || edges != d.Edges {
+		return "", fmt.Errorf("expected %d vertices and %d edges in space %s, but found %d vertices and %d edges",
+			d.Vertices, d.Edges, d.Space, vertices, edges)
+	}
+	return fmt.Sprintf("%d vertices and %d edges verified", vertices, edges), nil
+}
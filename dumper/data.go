@@ -0,0 +1,318 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package dumper
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/vesoft-inc/nebula-console/printer"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+)
+
+const (
+	// The rows fetched by one MATCH statement
+	DefaultPageSize = 1000
+	// The rows inserted by one INSERT statement
+	DefaultBatchSize = 100
+)
+
+// DataStats is the number of the dumped rows
+type DataStats struct {
+	Vertices int64
+	Edges    int64
+	Duration time.Duration
+	// Warnings are the differences from SHOW STATS, which may be stale, or the reason it's not compared
+	Warnings []string
+}
+
+func (s DataStats) String() string {
+	return fmt.Sprintf("%d vertices and %d edges dumped (time spent %v)", s.Vertices, s.Edges, s.Duration)
+}
+
+// DataDumper writes the vertices and edges of a space as batched INSERT statements.
+// The tags and edge types are scanned by MATCH ordered by the vids, or the src, dst and rank of the edges,
+// which needs an index on every tag and edge type, i.e. CREATE TAG INDEX i ON player(). The rows dumped
+// are compared with the existing SHOW STATS at the end, no job is submitted since the dump only reads.
+type DataDumper struct {
+	session *nebula.Session
+	schema  *SpaceSchema
+	w       *bufio.Writer
+	// Progress prints the number of the dumped rows
+	Progress  bool
+	PageSize  int
+	BatchSize int
+
+	stats DataStats
+	start time.Time
+	// The rows dumped of every tag and edge type
+	tagCounts  map[string]int64
+	edgeCounts map[string]int64
+}
+
+// NewDataDumper creates a dumper of the space, the session should have been switched to the space by FetchSchema
+func NewDataDumper(session *nebula.Session, schema *SpaceSchema, w io.Writer) *DataDumper {
+	return &DataDumper{
+		session:    session,
+		schema:     schema,
+		w:          bufio.NewWriter(w),
+		PageSize:   DefaultPageSize,
+		BatchSize:  DefaultBatchSize,
+		tagCounts:  make(map[string]int64),
+		edgeCounts: make(map[string]int64),
+	}
+}
+
+func (d *DataDumper) printProgress() {
+	if d.Progress {
+		fmt.Printf("\rDumped %d vertices, %d edges, elapsed %v",
+			d.stats.Vertices, d.stats.Edges, time.Since(d.start).Truncate(time.Second))
+	}
+}
+
+// scan pages the query by the key columns, which are the first keys columns of the rows, and writes
+// the values built from the rows in batches. The query returns the rows after the cursor ordered by
+// the keys, and the cursor is the literals of the keys of the last row, nil for the first page.
+// Paging by SKIP isn't used, since the rows are not in the same order between queries.
+func (d *DataDumper) scan(query func(cursor []string) string, keys int, header string, build func(values []string) string, count *int64) error {
+	var values []string
+	flush := func() {
+		if len(values) > 0 {
+			fmt.Fprintf(d.w, "%s%s;\n", header, strings.Join(values, ", "))
+			values = values[:0]
+		}
+	}
+	var cursor []string
+	for {
+		res, err := execute(d.session, query(cursor))
+		if err != nil {
+			return err
+		}
+		for i := 0; i < res.GetRowSize(); i++ {
+			record, err := res.GetRowValuesByIndex(i)
+			if err != nil {
+				return err
+			}
+			row, err := literals(record, 0, res.GetColSize())
+			if err != nil {
+				return err
+			}
+			values = append(values, build(row))
+			cursor = row[:keys]
+			*count++
+			if len(values) >= d.BatchSize {
+				flush()
+			}
+		}
+		d.printProgress()
+		if res.GetRowSize() < d.PageSize {
+			break
+		}
+	}
+	flush()
+	return nil
+}
+
+// literals converts the values of the record from the column start to nGQL literals
+func literals(record *nebula.Record, start, cols int) ([]string, error) {
+	var values []string
+	for i := start; i < cols; i++ {
+		val, err := record.GetValueByIndex(i)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, printer.NGQLLiteral(val))
+	}
+	return values, nil
+}
+
+func propList(props []PropDef, prefix string) (string, string) {
+	var names, yields []string
+	for _, p := range props {
+		names = append(names, fmt.Sprintf("`%s`", p.Name))
+		yields = append(yields, fmt.Sprintf(", %s.`%s`", prefix, p.Name))
+	}
+	return strings.Join(names, ", "), strings.Join(yields, "")
+}
+
+func (d *DataDumper) dumpTag(tag SchemaObject) error {
+	def, err := ParseSchemaDef(tag.Create)
+	if err != nil {
+		return err
+	}
+	names, yields := propList(def.Props, fmt.Sprintf("v.`%s`", def.Name))
+	query := func(cursor []string) string {
+		where := ""
+		if cursor != nil {
+			where = fmt.Sprintf(" WHERE id(v) > %s", cursor[0])
+		}
+		return fmt.Sprintf("MATCH (v:`%s`)%s RETURN id(v) AS `vid`%s ORDER BY `vid` LIMIT %d",
+			def.Name, where, yields, d.PageSize)
+	}
+	header := fmt.Sprintf("INSERT VERTEX `%s`(%s) VALUES ", def.Name, names)
+	count := int64(0)
+	err = d.scan(query, 1, header, func(values []string) string {
+		return fmt.Sprintf("%s:(%s)", values[0], strings.Join(values[1:], ", "))
+	}, &count)
+	d.stats.Vertices += count
+	d.tagCounts[def.Name] = count
+	return err
+}
+
+func (d *DataDumper) dumpEdge(edge SchemaObject) error {
+	def, err := ParseSchemaDef(edge.Create)
+	if err != nil {
+		return err
+	}
+	names, yields := propList(def.Props, "e")
+	query := func(cursor []string) string {
+		where := ""
+		if cursor != nil {
+			// (src, dst, rank) > cursor
+			where = fmt.Sprintf(" WHERE src(e) > %[1]s OR (src(e) == %[1]s AND (dst(e) > %[2]s OR (dst(e) == %[2]s AND rank(e) > %[3]s)))",
+				cursor[0], cursor[1], cursor[2])
+		}
+		return fmt.Sprintf("MATCH ()-[e:`%s`]->()%s RETURN src(e) AS `src`, dst(e) AS `dst`, rank(e) AS `rank`%s ORDER BY `src`, `dst`, `rank` LIMIT %d",
+			def.Name, where, yields, d.PageSize)
+	}
+	header := fmt.Sprintf("INSERT EDGE `%s`(%s) VALUES ", def.Name, names)
+	count := int64(0)
+	err = d.scan(query, 3, header, func(values []string) string {
+		return fmt.Sprintf("%s->%s@%s:(%s)", values[0], values[1], values[2], strings.Join(values[3:], ", "))
+	}, &count)
+	d.stats.Edges += count
+	d.edgeCounts[def.Name] = count
+	return err
+}
+
+var indexOnRegexp = regexp.MustCompile("(?i)\\sON\\s+`?([^`\\s(]+)`?\\s*\\(")
+
+// checkIndexes returns an error if a tag or an edge type has no index, the MATCH without
+// an index fails in Nebula 3.x
+func (d *DataDumper) checkIndexes() error {
+	indexed := make(map[string]bool)
+	for _, idx := range d.schema.TagIndexes {
+		if m := indexOnRegexp.FindStringSubmatch(idx.Create); m != nil {
+			indexed["tag "+m[1]] = true
+		}
+	}
+	for _, idx := range d.schema.EdgeIndexes {
+		if m := indexOnRegexp.FindStringSubmatch(idx.Create); m != nil {
+			indexed["edge "+m[1]] = true
+		}
+	}
+	var missing []string
+	for _, tag := range d.schema.Tags {
+		if !indexed["tag "+tag.Name] {
+			missing = append(missing, fmt.Sprintf("CREATE TAG INDEX `%[1]s_index` ON `%[1]s`()", tag.Name))
+		}
+	}
+	for _, edge := range d.schema.Edges {
+		if !indexed["edge "+edge.Name] {
+			missing = append(missing, fmt.Sprintf("CREATE EDGE INDEX `%[1]s_index` ON `%[1]s`()", edge.Name))
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("the tags and edge types are scanned by MATCH, which needs an index on each of them, "+
+			"please create and rebuild the missing ones first, i.e. %s", strings.Join(missing, "; "))
+	}
+	return nil
+}
+
+// lastStats returns the stop time of the latest finished stats job, empty if there isn't one
+func (d *DataDumper) lastStats() (string, error) {
+	// Job Id, Command, Status, Start Time, Stop Time
+	res, err := execute(d.session, "SHOW JOBS")
+	if err != nil {
+		return "", err
+	}
+	ids, commands, statuses, stops := column(res, 0), column(res, 1), column(res, 2), column(res, 4)
+	last, stop := int64(-1), ""
+	for i := range ids {
+		id, err := strconv.ParseInt(ids[i], 10, 64)
+		if err == nil && commands[i] == "STATS" && statuses[i] == "FINISHED" && id > last {
+			last, stop = id, stops[i]
+		}
+	}
+	return stop, nil
+}
+
+// verify compares the rows dumped of every tag and edge type with the existing SHOW STATS, the
+// differences are warnings since the statistics may be collected before the data is changed
+func (d *DataDumper) verify() ([]string, error) {
+	stop, err := d.lastStats()
+	if err != nil {
+		return nil, err
+	}
+	if stop == "" {
+		return []string{"the rows dumped are not verified, since there are no statistics of the space, " +
+			"run SUBMIT JOB STATS before dumping to verify them"}, nil
+	}
+	// Type, Name, Count, i.e. Tag, player, 51
+	res, err := execute(d.session, "SHOW STATS")
+	if err != nil {
+		return nil, err
+	}
+	kinds, names, counts := column(res, 0), column(res, 1), column(res, 2)
+	var mismatches []string
+	for i := range kinds {
+		var dumped map[string]int64
+		switch kinds[i] {
+		case "Tag":
+			dumped = d.tagCounts
+		case "Edge":
+			dumped = d.edgeCounts
+		default:
+			continue
+		}
+		if count := strconv.FormatInt(dumped[names[i]], 10); count != counts[i] {
+			mismatches = append(mismatches, fmt.Sprintf("%s %s: %s dumped, %s in SHOW STATS",
+				strings.ToLower(kinds[i]), names[i], count, counts[i]))
+		}
+	}
+	if len(mismatches) > 0 {
+		return []string{fmt.Sprintf("the statistics collected by the stats job finished at %s may be stale, "+
+			"or the data is changed during the dump, %s", stop, strings.Join(mismatches, "; "))}, nil
+	}
+	return nil, nil
+}
+
+// Dump writes the schema script followed by the data, the INSERT statements are executed
+// after the schema is waited for by the script
+func (d *DataDumper) Dump() (DataStats, error) {
+	d.start = time.Now()
+	if err := d.checkIndexes(); err != nil {
+		return d.stats, err
+	}
+	if err := d.schema.WriteScript(d.w); err != nil {
+		return d.stats, err
+	}
+	for _, tag := range d.schema.Tags {
+		if err := d.dumpTag(tag); err != nil {
+			return d.stats, err
+		}
+	}
+	for _, edge := range d.schema.Edges {
+		if err := d.dumpEdge(edge); err != nil {
+			return d.stats, err
+		}
+	}
+	if d.Progress {
+		fmt.Println()
+	}
+	d.stats.Duration = time.Since(d.start)
+	if err := d.w.Flush(); err != nil {
+		return d.stats, err
+	}
+	warnings, err := d.verify()
+	d.stats.Warnings = warnings
+	return d.stats, err
+}
@@ -0,0 +1,53 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package dumper
+
+import (
+	"reflect"
+	"testing"
+
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	ttypes "github.com/vesoft-inc/nebula-go/v3/nebula"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+)
+
+func TestPropList(t *testing.T) {
+	names, yields := propList([]PropDef{{Name: "name"}, {Name: "start year"}}, "e")
+	if want := "`name`, `start year`"; names != want {
+		t.Errorf("propList names = %q, want %q", names, want)
+	}
+	if want := ", e.`name`, e.`start year`"; yields != want {
+		t.Errorf("propList yields = %q, want %q", yields, want)
+	}
+	if names, yields := propList(nil, "v"); names != "" || yields != "" {
+		t.Errorf("propList(nil) = %q, %q, want empty", names, yields)
+	}
+}
+
+func TestLiterals(t *testing.T) {
+	id, age := []byte("player100"), int64(42)
+	null := ttypes.NullType___NULL__
+	res, err := nebula.GenResultSet(&graph.ExecutionResponse{ErrorCode: ttypes.ErrorCode_SUCCEEDED, Data: &ttypes.DataSet{
+		ColumnNames: [][]byte{[]byte("id"), []byte("name"), []byte("age"), []byte("nick")},
+		Rows: []*ttypes.Row{{Values: []*ttypes.Value{
+			{SVal: id}, {SVal: []byte(`Tim "TD" Duncan`)}, {IVal: &age}, {NVal: &null},
+		}}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	record, err := res.GetRowValuesByIndex(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := literals(record, 1, res.GetColSize())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{`"Tim \"TD\" Duncan"`, "42", "NULL"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("literals = %q, want %q", got, want)
+	}
+}
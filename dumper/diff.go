@@ -0,0 +1,255 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package dumper
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Change is a difference between two schemas and the statement resolving it
+type Change struct {
+	Description string
+	Statement   string
+	// Destructive statements drop the schema or the data, or convert the data
+	Destructive bool
+}
+
+// Diff is the changes making the schema of the target match the source
+type Diff struct {
+	Source string
+	Target string
+	// Notes are the differences which can not be resolved by statements, i.e. vid_type
+	Notes []string
+	// The changes are ordered to be executed one by one
+	Changes []Change
+	// The indexes created by the changes, which are rebuilt for the existing data
+	rebuilds []string
+	// The console commands waiting for the created or altered schemas, and the created indexes
+	schemaWaits []string
+	indexWaits  []string
+}
+
+func (d *Diff) waitSchema(kind, name string) {
+	wait := fmt.Sprintf(":wait %s `%s`", strings.ToLower(kind), name)
+	for _, w := range d.schemaWaits {
+		if w == wait {
+			return
+		}
+	}
+	d.schemaWaits = append(d.schemaWaits, wait)
+}
+
+// Empty returns whether the schemas are the same
+func (d *Diff) Empty() bool {
+	return len(d.Notes) == 0 && len(d.Changes) == 0
+}
+
+func (d *Diff) add(destructive bool, stmt string, format string, args ...interface{}) {
+	d.Changes = append(d.Changes, Change{
+		Description: fmt.Sprintf(format, args...),
+		Statement:   stmt,
+		Destructive: destructive,
+	})
+}
+
+func objectMap(objects []SchemaObject) map[string]SchemaObject {
+	m := make(map[string]SchemaObject, len(objects))
+	for _, o := range objects {
+		m[o.Name] = o
+	}
+	return m
+}
+
+// DiffSchema compares the schema of the target with the source, the changes make the target match the source
+func DiffSchema(source, target *SpaceSchema) (*Diff, error) {
+	d := &Diff{Source: source.Space, Target: target.Space}
+
+	sourceOptions, targetOptions := SpaceOptions(source.CreateSpace), SpaceOptions(target.CreateSpace)
+	for _, key := range []string{"vid_type", "partition_num", "replica_factor", "charset", "collate"} {
+		s, sok := sourceOptions[key]
+		t, tok := targetOptions[key]
+		if sok && tok && !strings.EqualFold(s, t) {
+			d.Notes = append(d.Notes, fmt.Sprintf("space option %s: %s -> %s, it can not be altered", key, t, s))
+		}
+	}
+
+	// The indexes are dropped before the schemas they depend on are altered, and created after
+	type indexCreate struct {
+		kind   string
+		object SchemaObject
+		desc   string
+	}
+	var creates []indexCreate
+	for _, idx := range []struct {
+		kind           string
+		source, target []SchemaObject
+	}{
+		{kind: "TAG INDEX", source: source.TagIndexes, target: target.TagIndexes},
+		{kind: "EDGE INDEX", source: source.EdgeIndexes, target: target.EdgeIndexes},
+		{kind: "FULLTEXT INDEX", source: source.FulltextIndexes, target: target.FulltextIndexes},
+	} {
+		name := strings.ToLower(idx.kind)
+		sourceMap := objectMap(idx.source)
+		for _, t := range idx.target {
+			s, ok := sourceMap[t.Name]
+			if !ok {
+				d.add(true, fmt.Sprintf("DROP %s `%s`", idx.kind, t.Name), "%s `%s` only in %s", name, t.Name, target.Space)
+			} else if s.Create != t.Create {
+				d.add(true, fmt.Sprintf("DROP %s `%s`", idx.kind, t.Name), "%s `%s` differs, it is dropped and recreated", name, t.Name)
+			}
+		}
+		targetMap := objectMap(idx.target)
+		for _, s := range idx.source {
+			t, ok := targetMap[s.Name]
+			if !ok {
+				creates = append(creates, indexCreate{idx.kind, s, fmt.Sprintf("%s `%s` only in %s", name, s.Name, source.Space)})
+			} else if s.Create != t.Create {
+				creates = append(creates, indexCreate{idx.kind, s, fmt.Sprintf("%s `%s` is created as in %s", name, s.Name, source.Space)})
+			}
+		}
+	}
+
+	for _, kinds := range []struct {
+		kind           string
+		source, target []SchemaObject
+	}{
+		{kind: "TAG", source: source.Tags, target: target.Tags},
+		{kind: "EDGE", source: source.Edges, target: target.Edges},
+	} {
+		targetMap := objectMap(kinds.target)
+		for _, s := range kinds.source {
+			t, ok := targetMap[s.Name]
+			if !ok {
+				d.add(false, s.Create, "%s `%s` only in %s", strings.ToLower(kinds.kind), s.Name, source.Space)
+				d.waitSchema(kinds.kind, s.Name)
+				continue
+			}
+			if s.Create == t.Create {
+				continue
+			}
+			if err := d.alter(kinds.kind, s, t); err != nil {
+				return nil, err
+			}
+			d.waitSchema(kinds.kind, s.Name)
+		}
+		sourceMap := objectMap(kinds.source)
+		for _, t := range kinds.target {
+			if _, ok := sourceMap[t.Name]; !ok {
+				d.add(true, fmt.Sprintf("DROP %s `%s`", kinds.kind, t.Name),
+					"%s `%s` only in %s", strings.ToLower(kinds.kind), t.Name, target.Space)
+			}
+		}
+	}
+
+	for _, c := range creates {
+		d.add(false, c.object.Create, "%s", c.desc)
+		if c.kind != "FULLTEXT INDEX" {
+			d.rebuilds = append(d.rebuilds, fmt.Sprintf("REBUILD %s `%s`", c.kind, c.object.Name))
+			d.indexWaits = append(d.indexWaits, fmt.Sprintf(":wait index `%s`", c.object.Name))
+		}
+	}
+	return d, nil
+}
+
+// alter compares the properties, TTL and comment of a tag or an edge type.
+// The properties are added and changed before the TTL is altered, so the new TTL column
+// exists, and they are dropped after, so the old TTL column is not in use.
+func (d *Diff) alter(kind string, source, target SchemaObject) error {
+	s, err := ParseSchemaDef(source.Create)
+	if err != nil {
+		return err
+	}
+	t, err := ParseSchemaDef(target.Create)
+	if err != nil {
+		return err
+	}
+	name := strings.ToLower(kind)
+	for _, sp := range s.Props {
+		tp, ok := t.Prop(sp.Name)
+		if !ok {
+			d.add(false, fmt.Sprintf("ALTER %s `%s` ADD (`%s` %s)", kind, s.Name, sp.Name, sp.Def),
+				"%s `%s`: property `%s` %s only in %s", name, s.Name, sp.Name, sp.Def, d.Source)
+			continue
+		}
+		if !strings.EqualFold(sp.Def, tp.Def) {
+			d.add(true, fmt.Sprintf("ALTER %s `%s` CHANGE (`%s` %s)", kind, s.Name, sp.Name, sp.Def),
+				"%s `%s`: property `%s` %s -> %s", name, s.Name, sp.Name, tp.Def, sp.Def)
+		}
+	}
+	if s.TTLDuration != t.TTLDuration || s.TTLCol != t.TTLCol {
+		d.add(false, fmt.Sprintf("ALTER %s `%s` TTL_DURATION = %s, TTL_COL = %s", kind, s.Name, s.TTLDuration, s.TTLCol),
+			"%s `%s`: TTL (%s, %s) -> (%s, %s)", name, s.Name, t.TTLDuration, t.TTLCol, s.TTLDuration, s.TTLCol)
+	}
+	if s.Comment != t.Comment {
+		d.add(false, fmt.Sprintf("ALTER %s `%s` COMMENT = %s", kind, s.Name, s.Comment),
+			"%s `%s`: comment %s -> %s", name, s.Name, t.Comment, s.Comment)
+	}
+	for _, tp := range t.Props {
+		if _, ok := s.Prop(tp.Name); !ok {
+			d.add(true, fmt.Sprintf("ALTER %s `%s` DROP (`%s`)", kind, s.Name, tp.Name),
+				"%s `%s`: property `%s` only in %s", name, s.Name, tp.Name, d.Target)
+		}
+	}
+	return nil
+}
+
+// WriteReport writes the differences in a human readable form
+func (d *Diff) WriteReport(w io.Writer) error {
+	var b strings.Builder
+	if d.Empty() {
+		fmt.Fprintf(&b, "The schema of %s matches %s\n", d.Target, d.Source)
+	} else {
+		fmt.Fprintf(&b, "Differences to make %s match %s:\n", d.Target, d.Source)
+	}
+	for _, note := range d.Notes {
+		fmt.Fprintf(&b, "  ! %s\n", note)
+	}
+	for _, c := range d.Changes {
+		if c.Destructive {
+			fmt.Fprintf(&b, "  * %s [DESTRUCTIVE]\n", c.Description)
+		} else {
+			fmt.Fprintf(&b, "  * %s\n", c.Description)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteScript writes the statements making the target match the source, the destructive
+// statements are marked by a trailing comment
+func (d *Diff) WriteScript(w io.Writer) error {
+	var b strings.Builder
+	if len(d.Changes) > 0 {
+		fmt.Fprintf(&b, "USE `%s`;\n", d.Target)
+	}
+	waited := false
+	for _, c := range d.Changes {
+		// Wait for the new schemas before creating the indexes on them
+		if !waited && strings.HasPrefix(c.Statement, "CREATE") && strings.Contains(c.Statement, " INDEX ") {
+			for _, wait := range d.schemaWaits {
+				fmt.Fprintln(&b, wait)
+			}
+			waited = true
+		}
+		if c.Destructive {
+			fmt.Fprintf(&b, "%s; # DESTRUCTIVE\n", c.Statement)
+		} else {
+			fmt.Fprintf(&b, "%s;\n", c.Statement)
+		}
+	}
+	if len(d.rebuilds) > 0 {
+		for _, wait := range d.indexWaits {
+			fmt.Fprintln(&b, wait)
+		}
+		for _, stmt := range d.rebuilds {
+			fmt.Fprintf(&b, "%s;\n", stmt)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
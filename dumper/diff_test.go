@@ -0,0 +1,118 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package dumper
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiffSchema(t *testing.T) {
+	space := "CREATE SPACE `%s` (partition_num = 10, vid_type = FIXED_STRING(32))"
+	cases := []struct {
+		name           string
+		source, target *SpaceSchema
+		notes          []string
+		changes        []Change
+	}{
+		{
+			name:   "same",
+			source: &SpaceSchema{Space: "a", Tags: []SchemaObject{{Name: "t", Create: "CREATE TAG t(a int)"}}},
+			target: &SpaceSchema{Space: "b", Tags: []SchemaObject{{Name: "t", Create: "CREATE TAG t(a int)"}}},
+		},
+		{
+			name:   "space options",
+			source: &SpaceSchema{Space: "a", CreateSpace: strings.Replace(space, "32", "64", 1)},
+			target: &SpaceSchema{Space: "b", CreateSpace: space},
+			notes:  []string{"space option vid_type: FIXED_STRING(32) -> FIXED_STRING(64), it can not be altered"},
+		},
+		{
+			name:   "created and dropped",
+			source: &SpaceSchema{Space: "a", Tags: []SchemaObject{{Name: "t1", Create: "CREATE TAG t1(a int)"}}},
+			target: &SpaceSchema{Space: "b", Edges: []SchemaObject{{Name: "e1", Create: "CREATE EDGE e1()"}}},
+			changes: []Change{
+				{Description: "tag `t1` only in a", Statement: "CREATE TAG t1(a int)"},
+				{Description: "edge `e1` only in b", Statement: "DROP EDGE `e1`", Destructive: true},
+			},
+		},
+		{
+			name: "altered",
+			source: &SpaceSchema{Space: "a", Tags: []SchemaObject{{Name: "t",
+				Create: "CREATE TAG t(a int, b string, c int) ttl_duration = 100, ttl_col = \"c\", comment = \"new\""}}},
+			target: &SpaceSchema{Space: "b", Tags: []SchemaObject{{Name: "t",
+				Create: "CREATE TAG t(a int, b int, d int) ttl_duration = 0, ttl_col = \"\", comment = \"\""}}},
+			changes: []Change{
+				{Description: "tag `t`: property `b` int -> string", Statement: "ALTER TAG `t` CHANGE (`b` string)", Destructive: true},
+				{Description: "tag `t`: property `c` int only in a", Statement: "ALTER TAG `t` ADD (`c` int)"},
+				{Description: "tag `t`: TTL (0, \"\") -> (100, \"c\")", Statement: "ALTER TAG `t` TTL_DURATION = 100, TTL_COL = \"c\""},
+				{Description: "tag `t`: comment \"\" -> \"new\"", Statement: "ALTER TAG `t` COMMENT = \"new\""},
+				{Description: "tag `t`: property `d` only in b", Statement: "ALTER TAG `t` DROP (`d`)", Destructive: true},
+			},
+		},
+		{
+			name: "indexes",
+			source: &SpaceSchema{Space: "a", TagIndexes: []SchemaObject{
+				{Name: "i1", Create: "CREATE TAG INDEX i1 ON t(a)"},
+				{Name: "i2", Create: "CREATE TAG INDEX i2 ON t(a, b)"},
+			}},
+			target: &SpaceSchema{Space: "b", TagIndexes: []SchemaObject{
+				{Name: "i2", Create: "CREATE TAG INDEX i2 ON t(a)"},
+				{Name: "i3", Create: "CREATE TAG INDEX i3 ON t(b)"},
+			}},
+			// The indexes are dropped first and created last
+			changes: []Change{
+				{Description: "tag index `i2` differs, it is dropped and recreated", Statement: "DROP TAG INDEX `i2`", Destructive: true},
+				{Description: "tag index `i3` only in b", Statement: "DROP TAG INDEX `i3`", Destructive: true},
+				{Description: "tag index `i1` only in a", Statement: "CREATE TAG INDEX i1 ON t(a)"},
+				{Description: "tag index `i2` is created as in a", Statement: "CREATE TAG INDEX i2 ON t(a, b)"},
+			},
+		},
+	}
+	for _, c := range cases {
+		d, err := DiffSchema(c.source, c.target)
+		if err != nil {
+			t.Errorf("%s: DiffSchema failed, %s", c.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(d.Notes, c.notes) {
+			t.Errorf("%s: notes = %q, want %q", c.name, d.Notes, c.notes)
+		}
+		if !reflect.DeepEqual(d.Changes, c.changes) {
+			t.Errorf("%s: changes = %+v, want %+v", c.name, d.Changes, c.changes)
+		}
+		if d.Empty() != (c.notes == nil && c.changes == nil) {
+			t.Errorf("%s: Empty() = %t", c.name, d.Empty())
+		}
+	}
+}
+
+func TestDiffWriteScript(t *testing.T) {
+	source := &SpaceSchema{
+		Space:      "a",
+		Tags:       []SchemaObject{{Name: "t", Create: "CREATE TAG t(a int)"}},
+		TagIndexes: []SchemaObject{{Name: "i", Create: "CREATE TAG INDEX i ON t(a)"}},
+	}
+	target := &SpaceSchema{Space: "b", Edges: []SchemaObject{{Name: "e", Create: "CREATE EDGE e()"}}}
+	d, err := DiffSchema(source, target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b strings.Builder
+	if err := d.WriteScript(&b); err != nil {
+		t.Fatal(err)
+	}
+	want := "USE `b`;\n" +
+		"CREATE TAG t(a int);\n" +
+		"DROP EDGE `e`; # DESTRUCTIVE\n" +
+		":wait tag `t`\n" +
+		"CREATE TAG INDEX i ON t(a);\n" +
+		":wait index `i`\n" +
+		"REBUILD TAG INDEX `i`;\n"
+	if b.String() != want {
+		t.Errorf("WriteScript =\n%s\nwant\n%s", b.String(), want)
+	}
+}
@@ -0,0 +1,248 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package dumper
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// PropDef is a property of a tag or an edge type, Def is the rest of the definition
+// after the name, i.e. `string NOT NULL DEFAULT "" COMMENT "name"`
+type PropDef struct {
+	Name string
+	Def  string
+}
+
+// SchemaDef is a tag or an edge type parsed from its CREATE statement
+type SchemaDef struct {
+	Kind        string
+	Name        string
+	Props       []PropDef
+	TTLDuration string
+	TTLCol      string
+	Comment     string
+}
+
+// Prop returns the property by name
+func (s *SchemaDef) Prop(name string) (PropDef, bool) {
+	for _, p := range s.Props {
+		if p.Name == name {
+			return p, true
+		}
+	}
+	return PropDef{}, false
+}
+
+// splitTopLevel splits the text by sep outside of the quotes and the parentheses
+func splitTopLevel(text string, sep rune) []string {
+	var (
+		items []string
+		depth int
+		quote rune
+		start int
+	)
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case quote != 0:
+			if r == '\\' {
+				i++
+			} else if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'' || r == '`':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == sep && depth == 0:
+			items = append(items, string(runes[start:i]))
+			start = i + 1
+		}
+	}
+	return append(items, string(runes[start:]))
+}
+
+// closingParen returns the index of the parenthesis closing the one at open
+func closingParen(text string, open int) int {
+	depth := 0
+	var quote byte
+	for i := open; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote != 0:
+			if c == '\\' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// unquoteName removes the backquotes around the name
+func unquoteName(name string) string {
+	return strings.Trim(strings.TrimSpace(name), "`")
+}
+
+// splitName splits `name` rest or name rest
+func splitName(text string) (string, string) {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "`") {
+		if end := strings.Index(text[1:], "`"); end >= 0 {
+			return text[1 : end+1], strings.TrimSpace(text[end+2:])
+		}
+	}
+	if i := strings.IndexAny(text, " \t("); i >= 0 {
+		return text[:i], strings.TrimSpace(text[i:])
+	}
+	return text, ""
+}
+
+var optionRegexp = regexp.MustCompile(`(?i)^\s*(\w+)\s*=\s*(.*?)\s*$`)
+
+// parseOptions parses the options like `ttl_duration = 0, ttl_col = "", comment = ""`,
+// the keys are in lower case
+func parseOptions(text string) map[string]string {
+	options := make(map[string]string)
+	for _, item := range splitTopLevel(text, ',') {
+		if m := optionRegexp.FindStringSubmatch(item); m != nil {
+			options[strings.ToLower(m[1])] = m[2]
+		}
+	}
+	return options
+}
+
+var createSchemaRegexp = regexp.MustCompile(`(?is)^\s*CREATE\s+(TAG|EDGE)\s+(IF\s+NOT\s+EXISTS\s+)?`)
+
+// ParseSchemaDef parses the CREATE TAG or CREATE EDGE statement
+func ParseSchemaDef(create string) (*SchemaDef, error) {
+	stmt := oneLine(create)
+	m := createSchemaRegexp.FindStringSubmatch(stmt)
+	if m == nil {
+		return nil, fmt.Errorf("not a CREATE TAG or CREATE EDGE statement: %s", stmt)
+	}
+	name, rest := splitName(stmt[len(m[0]):])
+	open := strings.Index(rest, "(")
+	if open < 0 {
+		return nil, fmt.Errorf("missing properties in: %s", stmt)
+	}
+	end := closingParen(rest, open)
+	if end < 0 {
+		return nil, fmt.Errorf("unbalanced parentheses in: %s", stmt)
+	}
+	def := &SchemaDef{Kind: strings.ToUpper(m[1]), Name: name, TTLDuration: "0", TTLCol: `""`, Comment: `""`}
+	for _, item := range splitTopLevel(rest[open+1:end], ',') {
+		if strings.TrimSpace(item) == "" {
+			continue
+		}
+		propName, propDef := splitName(item)
+		def.Props = append(def.Props, PropDef{Name: propName, Def: propDef})
+	}
+	options := parseOptions(strings.TrimSuffix(strings.TrimSpace(rest[end+1:]), ";"))
+	if v, ok := options["ttl_duration"]; ok {
+		def.TTLDuration = v
+	}
+	if v, ok := options["ttl_col"]; ok {
+		def.TTLCol = v
+	}
+	if v, ok := options["comment"]; ok {
+		def.Comment = v
+	}
+	return def, nil
+}
+
+// SpaceOptions parses the options of the CREATE SPACE statement, i.e. partition_num and vid_type
+func SpaceOptions(create string) map[string]string {
+	stmt := oneLine(create)
+	open := strings.Index(stmt, "(")
+	if open < 0 {
+		return nil
+	}
+	end := closingParen(stmt, open)
+	if end < 0 {
+		return nil
+	}
+	return parseOptions(stmt[open+1 : end])
+}
+
+var (
+	createSpaceScriptRegexp = regexp.MustCompile(`(?i)^CREATE\s+SPACE\s+(IF\s+NOT\s+EXISTS\s+)?`)
+	useScriptRegexp         = regexp.MustCompile(`(?i)^USE\s+`)
+	createScriptRegexp      = regexp.MustCompile(`(?i)^CREATE\s+(TAG\s+INDEX|EDGE\s+INDEX|TAG|EDGE|FULLTEXT\s+(TAG|EDGE)\s+INDEX)\s+(IF\s+NOT\s+EXISTS\s+)?`)
+)
+
+// ParseScript parses a script written by WriteScript, the statements other than
+// CREATE and USE are ignored
+func ParseScript(r io.Reader) (*SpaceSchema, error) {
+	schema := &SpaceSchema{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, ":") {
+			continue
+		}
+		for _, stmt := range splitTopLevel(line, ';') {
+			stmt = oneLine(stmt)
+			if stmt == "" {
+				continue
+			}
+			if m := createSpaceScriptRegexp.FindStringSubmatch(stmt); m != nil {
+				schema.Space, _ = splitName(stmt[len(m[0]):])
+				schema.CreateSpace = ifNotExists(stmt)
+				continue
+			}
+			if m := useScriptRegexp.FindStringSubmatch(stmt); m != nil {
+				if schema.Space == "" {
+					schema.Space = unquoteName(stmt[len(m[0]):])
+				}
+				continue
+			}
+			m := createScriptRegexp.FindStringSubmatch(stmt)
+			if m == nil {
+				continue
+			}
+			name, _ := splitName(stmt[len(m[0]):])
+			object := SchemaObject{Name: name, Create: ifNotExists(stmt)}
+			switch strings.ToUpper(spacesRegexp.ReplaceAllString(m[1], " ")) {
+			case "TAG":
+				schema.Tags = append(schema.Tags, object)
+			case "EDGE":
+				schema.Edges = append(schema.Edges, object)
+			case "TAG INDEX":
+				schema.TagIndexes = append(schema.TagIndexes, object)
+			case "EDGE INDEX":
+				schema.EdgeIndexes = append(schema.EdgeIndexes, object)
+			default:
+				schema.FulltextIndexes = append(schema.FulltextIndexes, object)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if schema.Space == "" {
+		return nil, fmt.Errorf("no CREATE SPACE or USE statement found")
+	}
+	return schema, nil
+}
@@ -0,0 +1,107 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package dumper
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseSchemaDef(t *testing.T) {
+	cases := []struct {
+		create string
+		want   *SchemaDef
+	}{
+		{
+			"CREATE TAG `player` (\n  `name` string NULL,\n  `age` int64 NULL DEFAULT 18\n) ttl_duration = 0, ttl_col = \"\", comment = \"\"",
+			&SchemaDef{Kind: "TAG", Name: "player", TTLDuration: "0", TTLCol: `""`, Comment: `""`, Props: []PropDef{
+				{Name: "name", Def: "string NULL"},
+				{Name: "age", Def: "int64 NULL DEFAULT 18"},
+			}},
+		},
+		{
+			"CREATE EDGE IF NOT EXISTS follow (degree int, since timestamp DEFAULT now()) TTL_DURATION = 100, TTL_COL = \"since\", COMMENT = \"a, b\";",
+			&SchemaDef{Kind: "EDGE", Name: "follow", TTLDuration: "100", TTLCol: `"since"`, Comment: `"a, b"`, Props: []PropDef{
+				{Name: "degree", Def: "int"},
+				{Name: "since", Def: "timestamp DEFAULT now()"},
+			}},
+		},
+		{
+			// The commas and the parentheses in the strings are not separators
+			"CREATE TAG t(`a b` string DEFAULT \"x, (y\" COMMENT 'c')",
+			&SchemaDef{Kind: "TAG", Name: "t", TTLDuration: "0", TTLCol: `""`, Comment: `""`, Props: []PropDef{
+				{Name: "a b", Def: "string DEFAULT \"x, (y\" COMMENT 'c'"},
+			}},
+		},
+		{"CREATE TAG empty()", &SchemaDef{Kind: "TAG", Name: "empty", TTLDuration: "0", TTLCol: `""`, Comment: `""`}},
+	}
+	for _, c := range cases {
+		got, err := ParseSchemaDef(c.create)
+		if err != nil {
+			t.Errorf("ParseSchemaDef(%q) failed, %s", c.create, err)
+			continue
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("ParseSchemaDef(%q) = %+v, want %+v", c.create, got, c.want)
+		}
+	}
+
+	for _, create := range []string{"CREATE SPACE s(vid_type = INT64)", "CREATE TAG t", "CREATE TAG t(a int"} {
+		if _, err := ParseSchemaDef(create); err == nil {
+			t.Errorf("ParseSchemaDef(%q) succeeded, want an error", create)
+		}
+	}
+}
+
+func TestSpaceOptions(t *testing.T) {
+	got := SpaceOptions("CREATE SPACE `nba` (partition_num = 10, replica_factor = 1, vid_type = FIXED_STRING(32), charset = utf8)")
+	want := map[string]string{"partition_num": "10", "replica_factor": "1", "vid_type": "FIXED_STRING(32)", "charset": "utf8"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("SpaceOptions = %v, want %v", got, want)
+	}
+	if got := SpaceOptions("CREATE SPACE nba"); got != nil {
+		t.Errorf("SpaceOptions without options = %v, want nil", got)
+	}
+}
+
+func TestParseScript(t *testing.T) {
+	schema := &SpaceSchema{
+		Space:           "nba",
+		CreateSpace:     "CREATE SPACE IF NOT EXISTS `nba` (partition_num = 10, vid_type = FIXED_STRING(32))",
+		Tags:            []SchemaObject{{Name: "player", Create: "CREATE TAG IF NOT EXISTS `player` (`name` string NULL) ttl_duration = 0, ttl_col = \"\", comment = \"\""}},
+		Edges:           []SchemaObject{{Name: "follow", Create: "CREATE EDGE IF NOT EXISTS `follow` (`degree` int64 NULL) ttl_duration = 0, ttl_col = \"\", comment = \"\""}},
+		TagIndexes:      []SchemaObject{{Name: "player_index", Create: "CREATE TAG INDEX IF NOT EXISTS `player_index` ON `player` (`name`(20))"}},
+		EdgeIndexes:     []SchemaObject{{Name: "follow_index", Create: "CREATE EDGE INDEX IF NOT EXISTS `follow_index` ON `follow` ()"}},
+		FulltextIndexes: []SchemaObject{{Name: "nebula_player_name", Create: "CREATE FULLTEXT TAG INDEX IF NOT EXISTS `nebula_player_name` ON `player`(name)"}},
+	}
+	var b strings.Builder
+	if err := schema.WriteScript(&b); err != nil {
+		t.Fatal(err)
+	}
+	// The script written is parsed back
+	got, err := ParseScript(strings.NewReader(b.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, schema) {
+		t.Errorf("ParseScript(WriteScript) = %+v, want %+v", got, schema)
+	}
+
+	// The CREATE statements are made idempotent, the space is taken from USE without CREATE SPACE
+	got, err = ParseScript(strings.NewReader("USE `s`;\n:wait tag t\nCREATE TAG t(a int); INSERT VERTEX t(a) VALUES 1:(1);\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &SpaceSchema{Space: "s", Tags: []SchemaObject{{Name: "t", Create: "CREATE TAG IF NOT EXISTS t(a int)"}}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseScript = %+v, want %+v", got, want)
+	}
+
+	if _, err := ParseScript(strings.NewReader("CREATE TAG t(a int);\n")); err == nil {
+		t.Errorf("ParseScript without the space succeeded, want an error")
+	}
+}
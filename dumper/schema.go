@@ -0,0 +1,221 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package dumper
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"github.com/vesoft-inc/nebula-console/ngql"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+)
+
+// SchemaObject is a tag, an edge type or an index with its CREATE statement
+type SchemaObject struct {
+	Name   string
+	Create string
+}
+
+// SpaceSchema is the schema of a space fetched by SHOW CREATE statements
+type SpaceSchema struct {
+	Space           string
+	CreateSpace     string
+	Tags            []SchemaObject
+	Edges           []SchemaObject
+	TagIndexes      []SchemaObject
+	EdgeIndexes     []SchemaObject
+	FulltextIndexes []SchemaObject
+}
+
+func execute(session *nebula.Session, stmt string) (*nebula.ResultSet, error) {
+	res, err := session.Execute(stmt)
+	if err != nil {
+		return nil, err
+	}
+	if !res.IsSucceed() {
+		return nil, fmt.Errorf("an error occurred when executing: %s, [ERROR (%d)]: %s", stmt, res.GetErrorCode(), res.GetErrorMsg())
+	}
+	return res, nil
+}
+
+// column returns the texts of the column by index
+func column(res *nebula.ResultSet, index int) []string {
+	var texts []string
+	for i := 0; i < res.GetRowSize(); i++ {
+		record, err := res.GetRowValuesByIndex(i)
+		if err != nil {
+			continue
+		}
+		val, err := record.GetValueByIndex(index)
+		if err != nil {
+			continue
+		}
+		if s, err := val.AsString(); err == nil {
+			texts = append(texts, s)
+		} else {
+			texts = append(texts, val.String())
+		}
+	}
+	return texts
+}
+
+var spacesRegexp = regexp.MustCompile(`\s+`)
+
+// The line breaks in the string literals are escaped
+var lineBreakReplacer = strings.NewReplacer("\r", `\r`, "\n", `\n`)
+
+// oneLine joins the statement into one line, since every line of a script is a statement.
+// The whitespaces are collapsed out of the string literals, i.e. DEFAULT and COMMENT are kept.
+func oneLine(stmt string) string {
+	var b strings.Builder
+	for _, t := range ngql.Tokenize(strings.TrimSpace(stmt)) {
+		switch t.Kind {
+		case ngql.Space:
+			b.WriteString(" ")
+		case ngql.String, ngql.QuotedName:
+			b.WriteString(lineBreakReplacer.Replace(t.Text))
+		default:
+			b.WriteString(t.Text)
+		}
+	}
+	return b.String()
+}
+
+var createRegexp = regexp.MustCompile(`(?i)^CREATE\s+(SPACE|TAG\s+INDEX|EDGE\s+INDEX|TAG|EDGE)\s+(IF\s+NOT\s+EXISTS\s+)?`)
+
+// ifNotExists makes the CREATE statement idempotent
+func ifNotExists(stmt string) string {
+	return createRegexp.ReplaceAllStringFunc(oneLine(stmt), func(s string) string {
+		m := createRegexp.FindStringSubmatch(s)
+		return fmt.Sprintf("CREATE %s IF NOT EXISTS ", strings.ToUpper(spacesRegexp.ReplaceAllString(m[1], " ")))
+	})
+}
+
+// showCreate fetches the CREATE statements of the objects listed by the SHOW statement
+func showCreate(session *nebula.Session, show, kind string) ([]SchemaObject, error) {
+	res, err := execute(session, show)
+	if err != nil {
+		return nil, err
+	}
+	var objects []SchemaObject
+	for _, name := range column(res, 0) {
+		res, err := execute(session, fmt.Sprintf("SHOW CREATE %s `%s`", kind, name))
+		if err != nil {
+			return nil, err
+		}
+		creates := column(res, 1)
+		if len(creates) != 1 {
+			return nil, fmt.Errorf("unexpected result of SHOW CREATE %s %s", kind, name)
+		}
+		objects = append(objects, SchemaObject{Name: name, Create: ifNotExists(creates[0])})
+	}
+	return objects, nil
+}
+
+// fulltextIndexes fetches the full-text indexes, whose columns are
+// Name, Schema Type, Schema Name, Fields and Analyzer
+func fulltextIndexes(session *nebula.Session) ([]SchemaObject, error) {
+	res, err := execute(session, "SHOW FULLTEXT INDEXES")
+	if err != nil {
+		return nil, err
+	}
+	var objects []SchemaObject
+	for i := 0; i < res.GetRowSize(); i++ {
+		record, err := res.GetRowValuesByIndex(i)
+		if err != nil {
+			return nil, err
+		}
+		var fields []string
+		for j := 0; j < res.GetColSize(); j++ {
+			val, err := record.GetValueByIndex(j)
+			if err != nil {
+				return nil, err
+			}
+			if s, err := val.AsString(); err == nil {
+				fields = append(fields, s)
+			} else {
+				fields = append(fields, val.String())
+			}
+		}
+		if len(fields) < 4 {
+			continue
+		}
+		create := fmt.Sprintf("CREATE FULLTEXT %s INDEX IF NOT EXISTS `%s` ON `%s`(%s)", strings.ToUpper(fields[1]), fields[0], fields[2], fields[3])
+		if len(fields) > 4 && fields[4] != "" {
+			create += fmt.Sprintf(" ANALYZER=\"%s\"", fields[4])
+		}
+		objects = append(objects, SchemaObject{Name: fields[0], Create: create})
+	}
+	return objects, nil
+}
+
+// FetchSchema fetches the schema of the space, the session will be switched to the space
+func FetchSchema(session *nebula.Session, space string) (*SpaceSchema, error) {
+	res, err := execute(session, fmt.Sprintf("SHOW CREATE SPACE `%s`", space))
+	if err != nil {
+		return nil, err
+	}
+	creates := column(res, 1)
+	if len(creates) != 1 {
+		return nil, fmt.Errorf("space %s not found", space)
+	}
+	schema := &SpaceSchema{Space: space, CreateSpace: ifNotExists(creates[0])}
+	if _, err := execute(session, fmt.Sprintf("USE `%s`", space)); err != nil {
+		return nil, err
+	}
+	if schema.Tags, err = showCreate(session, "SHOW TAGS", "TAG"); err != nil {
+		return nil, err
+	}
+	if schema.Edges, err = showCreate(session, "SHOW EDGES", "EDGE"); err != nil {
+		return nil, err
+	}
+	if schema.TagIndexes, err = showCreate(session, "SHOW TAG INDEXES", "TAG INDEX"); err != nil {
+		return nil, err
+	}
+	if schema.EdgeIndexes, err = showCreate(session, "SHOW EDGE INDEXES", "EDGE INDEX"); err != nil {
+		return nil, err
+	}
+	// Full-text indexes are not available without the listeners, they are skipped on error
+	if schema.FulltextIndexes, err = fulltextIndexes(session); err != nil {
+		schema.FulltextIndexes = nil
+	}
+	return schema, nil
+}
+
+// WriteScript writes the schema as a nGQL script, which is ordered by the dependencies:
+// space, tags and edge types, indexes and then full-text indexes. The console command
+// `:wait` waits for the schema changes propagated by heartbeat when replaying the script.
+func (s *SpaceSchema) WriteScript(w io.Writer) error {
+	var b strings.Builder
+	fmt.Fprintln(&b, s.CreateSpace+";")
+	fmt.Fprintf(&b, ":wait space `%s`\n", s.Space)
+	fmt.Fprintf(&b, "USE `%s`;\n", s.Space)
+	for _, objects := range [][]SchemaObject{s.Tags, s.Edges} {
+		for _, o := range objects {
+			fmt.Fprintln(&b, o.Create+";")
+		}
+	}
+	for _, o := range s.Tags {
+		fmt.Fprintf(&b, ":wait tag `%s`\n", o.Name)
+	}
+	for _, o := range s.Edges {
+		fmt.Fprintf(&b, ":wait edge `%s`\n", o.Name)
+	}
+	for _, objects := range [][]SchemaObject{s.TagIndexes, s.EdgeIndexes, s.FulltextIndexes} {
+		for _, o := range objects {
+			fmt.Fprintln(&b, o.Create+";")
+		}
+	}
+	for _, objects := range [][]SchemaObject{s.TagIndexes, s.EdgeIndexes} {
+		for _, o := range objects {
+			fmt.Fprintf(&b, ":wait index `%s`\n", o.Name)
+		}
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
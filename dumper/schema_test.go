@@ -0,0 +1,71 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package dumper
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestOneLine(t *testing.T) {
+	cases := []struct {
+		stmt string
+		want string
+	}{
+		{"CREATE TAG t(\n  a int,\n  b string\n)", "CREATE TAG t( a int, b string )"},
+		// The literals are kept, and their line breaks are escaped
+		{"CREATE TAG t(a string DEFAULT \"x  y\" COMMENT 'l1\nl2')", "CREATE TAG t(a string DEFAULT \"x  y\" COMMENT 'l1\\nl2')"},
+		{"  USE   `my  space`  ", "USE `my  space`"},
+	}
+	for _, c := range cases {
+		if got := oneLine(c.stmt); got != c.want {
+			t.Errorf("oneLine(%q) = %q, want %q", c.stmt, got, c.want)
+		}
+	}
+}
+
+func TestIfNotExists(t *testing.T) {
+	cases := []struct {
+		stmt string
+		want string
+	}{
+		{"CREATE SPACE `nba` (vid_type = INT64)", "CREATE SPACE IF NOT EXISTS `nba` (vid_type = INT64)"},
+		{"create tag  index\n`i` ON t()", "CREATE TAG INDEX IF NOT EXISTS `i` ON t()"},
+		{"CREATE EDGE IF NOT EXISTS e()", "CREATE EDGE IF NOT EXISTS e()"},
+	}
+	for _, c := range cases {
+		if got := ifNotExists(c.stmt); got != c.want {
+			t.Errorf("ifNotExists(%q) = %q, want %q", c.stmt, got, c.want)
+		}
+	}
+}
+
+func TestWriteScript(t *testing.T) {
+	schema := &SpaceSchema{
+		Space:       "nba",
+		CreateSpace: "CREATE SPACE IF NOT EXISTS `nba` (vid_type = INT64)",
+		Tags:        []SchemaObject{{Name: "player", Create: "CREATE TAG IF NOT EXISTS `player` (`name` string NULL)"}},
+		Edges:       []SchemaObject{{Name: "follow", Create: "CREATE EDGE IF NOT EXISTS `follow` ()"}},
+		TagIndexes:  []SchemaObject{{Name: "player_index", Create: "CREATE TAG INDEX IF NOT EXISTS `player_index` ON `player` (`name`(20))"}},
+	}
+	var b strings.Builder
+	if err := schema.WriteScript(&b); err != nil {
+		t.Fatal(err)
+	}
+	// The indexes follow the tags and edge types they are built on
+	want := "CREATE SPACE IF NOT EXISTS `nba` (vid_type = INT64);\n" +
+		":wait space `nba`\n" +
+		"USE `nba`;\n" +
+		"CREATE TAG IF NOT EXISTS `player` (`name` string NULL);\n" +
+		"CREATE EDGE IF NOT EXISTS `follow` ();\n" +
+		":wait tag `player`\n" +
+		":wait edge `follow`\n" +
+		"CREATE TAG INDEX IF NOT EXISTS `player_index` ON `player` (`name`(20));\n" +
+		":wait index `player_index`\n"
+	if got := b.String(); got != want {
+		t.Errorf("WriteScript = %q, want %q", got, want)
+	}
+}
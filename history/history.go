@@ -0,0 +1,218 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package history
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/vesoft-inc/nebula-console/ngql"
+)
+
+// DefaultMaxSize is the number of the entries kept by default
+const DefaultMaxSize = 1000
+
+// Entry is a statement or a console command executed in the interactive console
+type Entry struct {
+	ID        int           `json:"id"`
+	Time      time.Time     `json:"time"`
+	Space     string        `json:"space,omitempty"`
+	Duration  time.Duration `json:"duration"`
+	Statement string        `json:"statement"`
+}
+
+// History is the entries of a connection profile stored as JSON Lines. The entries are appended
+// when they are added, and the file is compacted when it's opened and closed, which removes
+// the duplicated statements except the latest one and the oldest entries beyond MaxSize.
+type History struct {
+	path    string
+	maxSize int
+	entries []*Entry
+	nextID  int
+	file    *os.File
+}
+
+// Open loads the history of the file, which is created with mode 0600 if not existed.
+// The mode of an existing file is changed to 0600, since the statements may be sensitive.
+func Open(path string, maxSize int) (*History, error) {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	h := &History{path: path, maxSize: maxSize, nextID: 1}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(path, 0600); err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err == nil {
+		err = h.read(f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err := h.compact(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+func (h *History) read(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var e Entry
+		// The lines broken by a crash are skipped
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil || e.Statement == "" {
+			continue
+		}
+		h.add(&e)
+	}
+	return scanner.Err()
+}
+
+// add keeps the entry and removes the previous one of the same statement
+func (h *History) add(e *Entry) {
+	for i, old := range h.entries {
+		if old.Statement == e.Statement {
+			h.entries = append(h.entries[:i], h.entries[i+1:]...)
+			break
+		}
+	}
+	if e.ID == 0 {
+		e.ID = h.nextID
+	}
+	if e.ID >= h.nextID {
+		h.nextID = e.ID + 1
+	}
+	h.entries = append(h.entries, e)
+	if len(h.entries) > h.maxSize {
+		h.entries = h.entries[len(h.entries)-h.maxSize:]
+	}
+}
+
+// compact rewrites the file by the entries kept, and reopens it for appending
+func (h *History) compact() error {
+	if h.file != nil {
+		h.file.Close()
+		h.file = nil
+	}
+	tmp := h.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	// The file left by a crash keeps its mode when it's truncated
+	if err := f.Chmod(0600); err != nil {
+		f.Close()
+		return err
+	}
+	w := bufio.NewWriter(f)
+	enc := json.NewEncoder(w)
+	for _, e := range h.entries {
+		if err := enc.Encode(e); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, h.path); err != nil {
+		return err
+	}
+	h.file, err = os.OpenFile(h.path, os.O_WRONLY|os.O_APPEND, 0600)
+	return err
+}
+
+// Import adds the statements without metadata, i.e. the lines of the plain history file
+// of the older versions, which may have the credentials not masked
+func (h *History) Import(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			h.add(&Entry{Statement: ngql.Redact(line)})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return h.compact()
+}
+
+// Add records the statement, the entry with the ID assigned is returned
+func (h *History) Add(e Entry) (*Entry, error) {
+	e.ID = 0
+	h.add(&e)
+	b, err := json.Marshal(&e)
+	if err != nil {
+		return nil, err
+	}
+	_, err = h.file.Write(append(b, '\n'))
+	return &e, err
+}
+
+// Entries returns the entries from the oldest
+func (h *History) Entries() []*Entry {
+	return h.entries
+}
+
+// Last returns the latest n entries
+func (h *History) Last(n int) []*Entry {
+	if n <= 0 || n > len(h.entries) {
+		n = len(h.entries)
+	}
+	return h.entries[len(h.entries)-n:]
+}
+
+// Grep returns the entries whose statements match the pattern
+func (h *History) Grep(pattern *regexp.Regexp) []*Entry {
+	var entries []*Entry
+	for _, e := range h.entries {
+		if pattern.MatchString(e.Statement) {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// Get returns the entry by ID
+func (h *History) Get(id int) (*Entry, bool) {
+	for _, e := range h.entries {
+		if e.ID == id {
+			return e, true
+		}
+	}
+	return nil, false
+}
+
+// Statements returns the statements from the oldest, which are recalled in the terminal
+func (h *History) Statements() []string {
+	statements := make([]string, 0, len(h.entries))
+	for _, e := range h.entries {
+		statements = append(statements, e.Statement)
+	}
+	return statements
+}
+
+// Close compacts the file
+func (h *History) Close() error {
+	return h.compact()
+}
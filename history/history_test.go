@@ -0,0 +1,137 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package history
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func open(t *testing.T, path string, maxSize int) *History {
+	h, err := Open(path, maxSize)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return h
+}
+
+func ids(entries []*Entry) []int {
+	var result []int
+	for _, e := range entries {
+		result = append(result, e.ID)
+	}
+	return result
+}
+
+func TestHistory(t *testing.T) {
+	cases := []struct {
+		name       string
+		maxSize    int
+		statements []string
+		// the statements kept and their IDs after reopening
+		want    []string
+		wantIDs []int
+	}{
+		{"kept", 10, []string{"a", "b", "c"}, []string{"a", "b", "c"}, []int{1, 2, 3}},
+		// The duplicated statements are moved to the latest
+		{"duplicated", 10, []string{"a", "b", "a"}, []string{"b", "a"}, []int{2, 3}},
+		{"oldest removed", 2, []string{"a", "b", "c"}, []string{"b", "c"}, []int{2, 3}},
+	}
+	for _, c := range cases {
+		path := filepath.Join(t.TempDir(), "profiles", "default.jsonl")
+		h := open(t, path, c.maxSize)
+		for _, stmt := range c.statements {
+			if _, err := h.Add(Entry{Statement: stmt, Space: "nba"}); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if got := h.Statements(); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: Statements = %v, want %v", c.name, got, c.want)
+		}
+		// The file is appended without compacting until it's closed, the reopened one is compacted
+		h.file.Close()
+		h = open(t, path, c.maxSize)
+		if got := h.Statements(); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: reopened Statements = %v, want %v", c.name, got, c.want)
+		}
+		if got := ids(h.Entries()); !reflect.DeepEqual(got, c.wantIDs) {
+			t.Errorf("%s: reopened IDs = %v, want %v", c.name, got, c.wantIDs)
+		}
+		e, err := h.Add(Entry{Statement: "next"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := c.wantIDs[len(c.wantIDs)-1] + 1; e.ID != want {
+			t.Errorf("%s: ID of the next entry = %d, want %d", c.name, e.ID, want)
+		}
+		h.Close()
+	}
+}
+
+func TestHistorySearch(t *testing.T) {
+	h := open(t, filepath.Join(t.TempDir(), "h.jsonl"), 0)
+	defer h.Close()
+	for _, stmt := range []string{"SHOW SPACES", "USE nba", "show tags", "MATCH (v) RETURN v"} {
+		h.Add(Entry{Statement: stmt})
+	}
+	cases := []struct {
+		name string
+		got  []*Entry
+		want []int
+	}{
+		{"Last(2)", h.Last(2), []int{3, 4}},
+		{"Last(0)", h.Last(0), []int{1, 2, 3, 4}},
+		{"Last(10)", h.Last(10), []int{1, 2, 3, 4}},
+		{"Grep(show)", h.Grep(regexp.MustCompile("(?i)show")), []int{1, 3}},
+		{"Grep(none)", h.Grep(regexp.MustCompile("DROP")), nil},
+	}
+	for _, c := range cases {
+		if got := ids(c.got); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s = %v, want %v", c.name, got, c.want)
+		}
+	}
+	if e, ok := h.Get(2); !ok || e.Statement != "USE nba" {
+		t.Errorf("Get(2) = %v, %t", e, ok)
+	}
+	if _, ok := h.Get(5); ok {
+		t.Errorf("Get(5) found an entry")
+	}
+}
+
+func TestHistoryFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "h.jsonl")
+	// The lines broken by a crash are skipped, and the file readable by others is restricted
+	content := `{"id":1,"statement":"SHOW SPACES"}` + "\n" + `{"id":2,"stat` + "\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	os.Chmod(path, 0644)
+	h := open(t, path, 0)
+	if got, want := h.Statements(), []string{"SHOW SPACES"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Statements = %v, want %v", got, want)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("mode of the history = %v, want 0600", info.Mode().Perm())
+	}
+
+	// The credentials of the plain history are masked
+	plain := "CREATE USER bob WITH PASSWORD 'secret'\n\nSHOW SPACES\nUSE nba\n"
+	if err := h.Import(strings.NewReader(plain)); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"CREATE USER bob WITH PASSWORD '***'", "SHOW SPACES", "USE nba"}
+	if got := h.Statements(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Statements after Import = %v, want %v", got, want)
+	}
+	h.Close()
+	if b, _ := os.ReadFile(path); strings.Contains(string(b), "secret") {
+		t.Errorf("the password is written to the history: %s", b)
+	}
+}
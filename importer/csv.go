@@ -0,0 +1,198 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package importer
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+)
+
+// :import csv file.csv INTO TAG player(vid=col0, name=col1, age=col2) [flags]
+var csvImportRegexp = regexp.MustCompile("(?is)^\\s*:import\\s+csv\\s+(\\S+)\\s+into\\s+(tag|edge)\\s+`?([^\\s(`]+)`?\\s*\\((.*)\\)(.*?)\\s*;?\\s*$")
+
+// CsvImport loads a csv file into a tag or an edge type
+type CsvImport struct {
+	File      string
+	Mapping   *Mapping
+	Header    bool
+	Delimiter rune
+	Options   Options
+}
+
+// ParseCsvImport parses the import command, the supported flags are
+// --header, --delimiter c, --batch-size n, --concurrency n and --reject-file file
+func ParseCsvImport(cmd string) (*CsvImport, error) {
+	matches := csvImportRegexp.FindStringSubmatch(cmd)
+	if matches == nil {
+		return nil, fmt.Errorf("wrong import command, usage: :import csv file.csv INTO TAG|EDGE name(vid=col0, prop=col1, ...)")
+	}
+	mapping, err := parseMappingList(matches[2], matches[3], matches[4])
+	if err != nil {
+		return nil, err
+	}
+	imp := &CsvImport{
+		File:      matches[1],
+		Mapping:   mapping,
+		Delimiter: ',',
+		Options: Options{
+			BatchSize:   DefaultBatchSize,
+			Concurrency: DefaultConcurrency,
+			RejectFile:  matches[1] + ".rejected.csv",
+		},
+	}
+
+	flags := strings.Fields(matches[5])
+	for i := 0; i < len(flags); i++ {
+		flag := flags[i]
+		if flag == "--header" {
+			imp.Header = true
+			continue
+		}
+		if i+1 >= len(flags) {
+			return nil, fmt.Errorf("missing value of flag %s", flag)
+		}
+		i++
+		value := flags[i]
+		switch flag {
+		case "--delimiter":
+			value = strings.Trim(value, "'\"")
+			if value == "\\t" {
+				value = "\t"
+			}
+			r, size := utf8.DecodeRuneInString(value)
+			if size != len(value) {
+				return nil, fmt.Errorf("invalid delimiter %s", value)
+			}
+			imp.Delimiter = r
+		case "--batch-size", "--concurrency":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid value of flag %s: %s", flag, value)
+			}
+			if flag == "--concurrency" && n > MaxConcurrency {
+				return nil, fmt.Errorf("concurrency should not be greater than %d", MaxConcurrency)
+			}
+			if flag == "--batch-size" {
+				imp.Options.BatchSize = n
+			} else {
+				imp.Options.Concurrency = n
+			}
+		case "--reject-file":
+			imp.Options.RejectFile = value
+		default:
+			return nil, fmt.Errorf("unknown flag %s", flag)
+		}
+	}
+	return imp, nil
+}
+
+// columnIndex resolves the column reference colN or the column name in the header
+func columnIndex(field string, header []string) (int, error) {
+	for i, name := range header {
+		if name == field {
+			return i, nil
+		}
+	}
+	if strings.HasPrefix(strings.ToLower(field), "col") {
+		if i, err := strconv.Atoi(field[3:]); err == nil && i >= 0 {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("unknown column %s", field)
+}
+
+// Run loads the file into the space, the schema is described by the session
+// and the batches are executed on the sessions created by newSession
+func (imp *CsvImport) Run(session *nebula.Session, space string, newSession func() (*nebula.Session, error)) (Stats, error) {
+	imp.Options.Space = space
+	imp.Options.NewSession = newSession
+
+	schema, err := DescribeSchema(session, imp.Options.Space, imp.Mapping.Kind, imp.Mapping.Name)
+	if err != nil {
+		return Stats{}, err
+	}
+	if err := imp.Mapping.Validate(schema); err != nil {
+		return Stats{}, err
+	}
+
+	fd, err := os.Open(imp.File)
+	if err != nil {
+		return Stats{}, err
+	}
+	defer fd.Close()
+	reader := csv.NewReader(fd)
+	reader.Comma = imp.Delimiter
+	reader.FieldsPerRecord = -1
+
+	var header []string
+	if imp.Header {
+		if header, err = reader.Read(); err != nil {
+			return Stats{}, fmt.Errorf("read header of %s failed, %s", imp.File, err.Error())
+		}
+	}
+
+	// resolve the column references once
+	columns := make(map[string]int)
+	fields := []string{imp.Mapping.Vid, imp.Mapping.Src, imp.Mapping.Dst, imp.Mapping.Rank}
+	for _, p := range imp.Mapping.Props {
+		fields = append(fields, p.Field)
+	}
+	for _, field := range fields {
+		if field == "" {
+			continue
+		}
+		i, err := columnIndex(field, header)
+		if err != nil {
+			return Stats{}, err
+		}
+		columns[field] = i
+	}
+
+	insertHeader := imp.Mapping.InsertHeader()
+	loader, err := NewLoader(imp.Options)
+	if err != nil {
+		return Stats{}, err
+	}
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				loader.Reject(Row{Line: parseErr.StartLine, Raw: record}, err)
+				continue
+			}
+			stats := loader.Close()
+			return stats, err
+		}
+		line, _ := reader.FieldPos(0)
+		lookup := func(field string) (string, bool) {
+			i := columns[field]
+			if i >= len(record) {
+				return "", false
+			}
+			return record[i], true
+		}
+		value, err := imp.Mapping.BuildValue(schema, lookup)
+		if err != nil {
+			loader.Reject(Row{Line: line, Raw: record}, err)
+			continue
+		}
+		loader.Add(insertHeader, Row{Line: line, Raw: record, Value: value})
+	}
+	return loader.Close(), nil
+}
@@ -0,0 +1,76 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package importer
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseCsvImport(t *testing.T) {
+	cases := []struct {
+		cmd     string
+		want    *CsvImport
+		wantErr bool
+	}{
+		{":import csv player.csv INTO TAG player(vid=col0, name=col1, age=col2)", &CsvImport{
+			File: "player.csv",
+			Mapping: &Mapping{Kind: KindTag, Name: "player", Vid: "col0",
+				Props: []PropMapping{{Prop: "name", Field: "col1"}, {Prop: "age", Field: "col2"}}},
+			Delimiter: ',',
+			Options:   Options{BatchSize: DefaultBatchSize, Concurrency: DefaultConcurrency, RejectFile: "player.csv.rejected.csv"},
+		}, false},
+		{":import csv serve.csv into edge `serve`(src=from, dst=to, rank=r, `start_year`=start) --header --delimiter '\\t' --batch-size 500 --concurrency 8 --reject-file bad.csv;", &CsvImport{
+			File: "serve.csv",
+			Mapping: &Mapping{Kind: KindEdge, Name: "serve", Src: "from", Dst: "to", Rank: "r",
+				Props: []PropMapping{{Prop: "start_year", Field: "start"}}},
+			Header:    true,
+			Delimiter: '\t',
+			Options:   Options{BatchSize: 500, Concurrency: 8, RejectFile: "bad.csv"},
+		}, false},
+		{":import csv player.csv INTO TAG player", nil, true},
+		{":import csv player.csv INTO TAG player(vid)", nil, true},
+		{":import csv player.csv INTO TAG player(vid=col0) --delimiter ||", nil, true},
+		{":import csv player.csv INTO TAG player(vid=col0) --batch-size 0", nil, true},
+		{":import csv player.csv INTO TAG player(vid=col0) --concurrency 100", nil, true},
+		{":import csv player.csv INTO TAG player(vid=col0) --reject-file", nil, true},
+		{":import csv player.csv INTO TAG player(vid=col0) --quiet 1", nil, true},
+	}
+	for _, c := range cases {
+		got, err := ParseCsvImport(c.cmd)
+		if (err != nil) != c.wantErr {
+			t.Errorf("ParseCsvImport(%q) error = %v, want error %t", c.cmd, err, c.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("ParseCsvImport(%q) = %+v, want %+v", c.cmd, got, c.want)
+		}
+	}
+}
+
+func TestColumnIndex(t *testing.T) {
+	header := []string{"id", "name", "col0"}
+	cases := []struct {
+		field   string
+		header  []string
+		want    int
+		wantErr bool
+	}{
+		{"col2", nil, 2, false},
+		{"COL10", nil, 10, false},
+		{"name", header, 1, false},
+		// The name in the header is preferred to the column reference
+		{"col0", header, 2, false},
+		{"name", nil, -1, true},
+		{"col-1", nil, -1, true},
+	}
+	for _, c := range cases {
+		got, err := columnIndex(c.field, c.header)
+		if got != c.want || (err != nil) != c.wantErr {
+			t.Errorf("columnIndex(%s, %v) = %d, %v, want %d, error %t", c.field, c.header, got, err, c.want, c.wantErr)
+		}
+	}
+}
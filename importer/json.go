@@ -0,0 +1,234 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+)
+
+// MappingFile is the content of the mapping file of JSON Lines import, i.e.
+//
+//	{
+//	  "mappings": [
+//	    {"kind": "tag", "name": "player", "vid": "id",
+//	     "props": [{"prop": "name", "field": "name"}, {"prop": "age", "field": "profile.age"}]},
+//	    {"kind": "edge", "name": "follow", "src": "id", "dst": "follows.id",
+//	     "props": [{"prop": "degree", "field": "follows.degree"}]}
+//	  ]
+//	}
+//
+// The fields are looked up by the dotted path in the JSON objects.
+type MappingFile struct {
+	Mappings []*Mapping `json:"mappings"`
+}
+
+// JsonImport loads a JSON Lines file, every line may generate a vertex or an edge for each mapping
+type JsonImport struct {
+	File        string
+	MappingFile string
+	Options     Options
+}
+
+// ParseJsonImport parses the import command, the supported flags are
+// --dry-run, --batch-size n and --reject-file file
+func ParseJsonImport(cmd string) (*JsonImport, error) {
+	words := strings.Fields(strings.TrimSuffix(strings.TrimSpace(cmd), ";"))
+	// :import jsonl file mapping [flags]
+	if len(words) < 4 {
+		return nil, fmt.Errorf("wrong import command, usage: :import jsonl file.jsonl mapping.json [--dry-run]")
+	}
+	imp := &JsonImport{
+		File:        words[2],
+		MappingFile: words[3],
+		Options: Options{
+			BatchSize:  DefaultBatchSize,
+			RejectFile: words[2] + ".rejected.csv",
+		},
+	}
+	flags := words[4:]
+	for i := 0; i < len(flags); i++ {
+		flag := flags[i]
+		if flag == "--dry-run" {
+			imp.Options.DryRun = true
+			continue
+		}
+		if i+1 >= len(flags) {
+			return nil, fmt.Errorf("missing value of flag %s", flag)
+		}
+		i++
+		value := flags[i]
+		switch flag {
+		case "--batch-size":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return nil, fmt.Errorf("invalid value of flag %s: %s", flag, value)
+			}
+			imp.Options.BatchSize = n
+		case "--reject-file":
+			imp.Options.RejectFile = value
+		default:
+			return nil, fmt.Errorf("unknown flag %s", flag)
+		}
+	}
+	return imp, nil
+}
+
+func loadMappingFile(filename string) (*MappingFile, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var m MappingFile
+	if err := json.Unmarshal(content, &m); err != nil {
+		return nil, fmt.Errorf("parse mapping file %s failed, %s", filename, err.Error())
+	}
+	if len(m.Mappings) == 0 {
+		return nil, fmt.Errorf("no mapping found in %s", filename)
+	}
+	for _, mapping := range m.Mappings {
+		mapping.Kind = strings.ToUpper(mapping.Kind)
+	}
+	return &m, nil
+}
+
+// lookupJson returns the text of the field at the dotted path,
+// objects and arrays are returned as JSON and null is treated as absent
+func lookupJson(obj map[string]interface{}, path string) (string, bool) {
+	var cur interface{} = obj
+	for _, key := range strings.Split(path, ".") {
+		m, ok := cur.(map[string]interface{})
+		if !ok {
+			return "", false
+		}
+		if cur, ok = m[key]; !ok {
+			return "", false
+		}
+	}
+	switch v := cur.(type) {
+	case nil:
+		return "", false
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		b, err := json.Marshal(v)
+		if err != nil {
+			return "", false
+		}
+		return string(b), true
+	}
+}
+
+// keyFields returns the fields which must be present for the mapping to apply
+func (m *Mapping) keyFields() []string {
+	if m.Kind == KindTag {
+		return []string{m.Vid}
+	}
+	return []string{m.Src, m.Dst}
+}
+
+// Run loads the file into the space over the session, the values are validated
+// against the schema described by the session
+func (imp *JsonImport) Run(session *nebula.Session, space string) (Stats, error) {
+	imp.Options.Space = space
+	imp.Options.Session = session
+
+	mappingFile, err := loadMappingFile(imp.MappingFile)
+	if err != nil {
+		return Stats{}, err
+	}
+	schemas := make([]*Schema, len(mappingFile.Mappings))
+	for i, mapping := range mappingFile.Mappings {
+		if schemas[i], err = DescribeSchema(session, space, mapping.Kind, mapping.Name); err != nil {
+			return Stats{}, err
+		}
+		if err := mapping.Validate(schemas[i]); err != nil {
+			return Stats{}, err
+		}
+	}
+
+	fd, err := os.Open(imp.File)
+	if err != nil {
+		return Stats{}, err
+	}
+	defer fd.Close()
+
+	loader, err := NewLoader(imp.Options)
+	if err != nil {
+		return Stats{}, err
+	}
+	scanner := bufio.NewScanner(fd)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := scanner.Text()
+		if len(strings.TrimSpace(text)) == 0 {
+			continue
+		}
+		row := Row{Line: line, Raw: []string{text}}
+		decoder := json.NewDecoder(bytes.NewReader(scanner.Bytes()))
+		decoder.UseNumber()
+		var obj map[string]interface{}
+		if err := decoder.Decode(&obj); err != nil {
+			loader.Reject(row, fmt.Errorf("invalid JSON, %s", err.Error()))
+			continue
+		}
+		lookup := func(field string) (string, bool) {
+			return lookupJson(obj, field)
+		}
+
+		// A mapping applies to the lines having its vid or src and dst, a line which no mapping
+		// applies to is rejected. The values of all mappings are built before any is queued,
+		// so a line is either imported by all of them or rejected as a whole.
+		var (
+			headers []string
+			rows    []Row
+			rowErr  error
+		)
+		for i, mapping := range mappingFile.Mappings {
+			present := true
+			for _, field := range mapping.keyFields() {
+				_, ok := lookup(field)
+				present = present && ok
+			}
+			if !present {
+				continue
+			}
+			value, err := mapping.BuildValue(schemas[i], lookup)
+			if err != nil {
+				rowErr = fmt.Errorf("%s %s: %s", strings.ToLower(mapping.Kind), mapping.Name, err.Error())
+				break
+			}
+			headers = append(headers, mapping.InsertHeader())
+			rows = append(rows, Row{Line: row.Line, Raw: row.Raw, Value: value})
+		}
+		switch {
+		case rowErr != nil:
+			loader.Reject(row, rowErr)
+		case len(rows) == 0:
+			loader.Reject(row, fmt.Errorf("no mapping applies to the line"))
+		default:
+			loader.AddLine(headers, rows)
+		}
+	}
+	stats := loader.Close()
+	if err := scanner.Err(); err != nil {
+		return stats, err
+	}
+	return stats, nil
+}
@@ -0,0 +1,110 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package importer
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestParseJsonImport(t *testing.T) {
+	cases := []struct {
+		cmd     string
+		want    *JsonImport
+		wantErr bool
+	}{
+		{":import jsonl events.jsonl mapping.json", &JsonImport{File: "events.jsonl", MappingFile: "mapping.json",
+			Options: Options{BatchSize: DefaultBatchSize, RejectFile: "events.jsonl.rejected.csv"}}, false},
+		{":import jsonl events.jsonl mapping.json --dry-run --batch-size 200 --reject-file bad.csv;", &JsonImport{File: "events.jsonl", MappingFile: "mapping.json",
+			Options: Options{BatchSize: 200, RejectFile: "bad.csv", DryRun: true}}, false},
+		{":import jsonl events.jsonl", nil, true},
+		{":import jsonl events.jsonl mapping.json --batch-size x", nil, true},
+		{":import jsonl events.jsonl mapping.json --concurrency 2", nil, true},
+	}
+	for _, c := range cases {
+		got, err := ParseJsonImport(c.cmd)
+		if (err != nil) != c.wantErr {
+			t.Errorf("ParseJsonImport(%q) error = %v, want error %t", c.cmd, err, c.wantErr)
+			continue
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("ParseJsonImport(%q) = %+v, want %+v", c.cmd, got, c.want)
+		}
+	}
+}
+
+func TestLoadMappingFile(t *testing.T) {
+	dir := t.TempDir()
+	cases := []struct {
+		content string
+		want    []*Mapping
+		wantErr bool
+	}{
+		{`{"mappings": [{"kind": "tag", "name": "player", "vid": "id", "props": [{"prop": "age", "field": "profile.age"}]},
+			{"kind": "Edge", "name": "follow", "src": "id", "dst": "follows.id"}]}`, []*Mapping{
+			{Kind: KindTag, Name: "player", Vid: "id", Props: []PropMapping{{Prop: "age", Field: "profile.age"}}},
+			{Kind: KindEdge, Name: "follow", Src: "id", Dst: "follows.id"},
+		}, false},
+		{`{"mappings": []}`, nil, true},
+		{`{"mappings": [`, nil, true},
+	}
+	for i, c := range cases {
+		path := filepath.Join(dir, "mapping.json")
+		if err := os.WriteFile(path, []byte(c.content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		m, err := loadMappingFile(path)
+		if (err != nil) != c.wantErr {
+			t.Errorf("case %d: loadMappingFile error = %v, want error %t", i, err, c.wantErr)
+			continue
+		}
+		if err == nil && !reflect.DeepEqual(m.Mappings, c.want) {
+			t.Errorf("case %d: loadMappingFile = %+v, want %+v", i, m.Mappings, c.want)
+		}
+	}
+	if _, err := loadMappingFile(filepath.Join(dir, "missing.json")); err == nil {
+		t.Errorf("loadMappingFile of a missing file succeeded, want an error")
+	}
+}
+
+func TestLookupJson(t *testing.T) {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(
+		`{"id": "player100", "age": 42, "height": 2.11, "active": true, "nick": null,
+		  "profile": {"team": {"name": "Spurs"}}, "tags": ["a", "b"]}`)))
+	decoder.UseNumber()
+	var obj map[string]interface{}
+	if err := decoder.Decode(&obj); err != nil {
+		t.Fatal(err)
+	}
+	cases := []struct {
+		path string
+		want string
+		ok   bool
+	}{
+		{"id", "player100", true},
+		// The numbers keep their text
+		{"age", "42", true},
+		{"height", "2.11", true},
+		{"active", "true", true},
+		{"profile.team.name", "Spurs", true},
+		// Objects and arrays are JSON, null is absent
+		{"profile.team", `{"name":"Spurs"}`, true},
+		{"tags", `["a","b"]`, true},
+		{"nick", "", false},
+		{"missing", "", false},
+		{"id.name", "", false},
+	}
+	for _, c := range cases {
+		got, ok := lookupJson(obj, c.path)
+		if got != c.want || ok != c.ok {
+			t.Errorf("lookupJson(%s) = %q, %t, want %q, %t", c.path, got, ok, c.want, c.ok)
+		}
+	}
+}
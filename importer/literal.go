@@ -0,0 +1,152 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package importer
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/vesoft-inc/nebula-console/printer"
+)
+
+var (
+	dateRegexp     = regexp.MustCompile(`^\d{4}-\d{1,2}-\d{1,2}$`)
+	timeRegexp     = regexp.MustCompile(`^\d{1,2}:\d{1,2}(:\d{1,2}(\.\d{1,6})?)?$`)
+	datetimeRegexp = regexp.MustCompile(`^\d{4}-\d{1,2}-\d{1,2}[T ]\d{1,2}:\d{1,2}(:\d{1,2}(\.\d{1,6})?)?$`)
+)
+
+// durationKeys are the keys of the map given to duration()
+var durationKeys = map[string]bool{
+	"years": true, "months": true, "days": true, "hours": true,
+	"minutes": true, "seconds": true, "milliseconds": true, "microseconds": true,
+}
+
+// VidLiteral converts the text to a vid literal of the space vid type
+func VidLiteral(vidType, text string) (string, error) {
+	if strings.HasPrefix(strings.ToUpper(vidType), "INT") {
+		if _, err := strconv.ParseInt(strings.TrimSpace(text), 10, 64); err != nil {
+			return "", fmt.Errorf("invalid INT64 vid %q", text)
+		}
+		return strings.TrimSpace(text), nil
+	}
+	if len(text) == 0 {
+		return "", fmt.Errorf("empty vid")
+	}
+	return printer.QuoteNGQLString(text), nil
+}
+
+// Literal converts the text to a nGQL literal of the property type.
+// An empty text is NULL for the non-string types.
+func Literal(prop *Property, text string) (string, error) {
+	typ := prop.Type
+	isString := typ == "string" || strings.HasPrefix(typ, "fixed_string")
+	if isString {
+		return printer.QuoteNGQLString(text), nil
+	}
+	text = strings.TrimSpace(text)
+	if len(text) == 0 || strings.EqualFold(text, "null") {
+		if !prop.Nullable {
+			return "", fmt.Errorf("property %s is not nullable", prop.Name)
+		}
+		return "NULL", nil
+	}
+
+	switch {
+	case strings.HasPrefix(typ, "int"):
+		i, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("invalid %s value %q for property %s", typ, text, prop.Name)
+		}
+		return strconv.FormatInt(i, 10), nil
+	case typ == "float" || typ == "double":
+		f, err := strconv.ParseFloat(text, 64)
+		if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+			return "", fmt.Errorf("invalid %s value %q for property %s", typ, text, prop.Name)
+		}
+		// The decimal point keeps it a float literal rather than an int one
+		s := strconv.FormatFloat(f, 'f', -1, 64)
+		if !strings.Contains(s, ".") {
+			s += ".0"
+		}
+		return s, nil
+	case typ == "bool":
+		b, err := strconv.ParseBool(text)
+		if err != nil {
+			return "", fmt.Errorf("invalid bool value %q for property %s", text, prop.Name)
+		}
+		return strconv.FormatBool(b), nil
+	case typ == "timestamp":
+		if i, err := strconv.ParseInt(text, 10, 64); err == nil {
+			return strconv.FormatInt(i, 10), nil
+		}
+		if !datetimeRegexp.MatchString(text) {
+			return "", fmt.Errorf("invalid timestamp value %q for property %s", text, prop.Name)
+		}
+		return fmt.Sprintf("timestamp(%s)", printer.QuoteNGQLString(text)), nil
+	case typ == "date":
+		if !dateRegexp.MatchString(text) {
+			return "", fmt.Errorf("invalid date value %q for property %s", text, prop.Name)
+		}
+		return fmt.Sprintf("date(%s)", printer.QuoteNGQLString(text)), nil
+	case typ == "time":
+		if !timeRegexp.MatchString(text) {
+			return "", fmt.Errorf("invalid time value %q for property %s", text, prop.Name)
+		}
+		return fmt.Sprintf("time(%s)", printer.QuoteNGQLString(text)), nil
+	case typ == "datetime":
+		if !datetimeRegexp.MatchString(text) {
+			return "", fmt.Errorf("invalid datetime value %q for property %s", text, prop.Name)
+		}
+		return fmt.Sprintf("datetime(%s)", printer.QuoteNGQLString(strings.Replace(text, " ", "T", 1))), nil
+	case strings.HasPrefix(typ, "geography"):
+		return fmt.Sprintf("ST_GeogFromText(%s)", printer.QuoteNGQLString(text)), nil
+	case typ == "duration":
+		literal, err := durationLiteral(text)
+		if err != nil {
+			return "", fmt.Errorf("invalid duration value %q for property %s, %s", text, prop.Name, err)
+		}
+		return literal, nil
+	default:
+		return "", fmt.Errorf("unsupported type %s of property %s", typ, prop.Name)
+	}
+}
+
+// durationLiteral parses the duration given as a map, i.e. {days: 1, hours: 2},
+// and rebuilds it from the keys and the integers rather than pasting the text
+func durationLiteral(text string) (string, error) {
+	if !strings.HasPrefix(text, "{") || !strings.HasSuffix(text, "}") {
+		return "", fmt.Errorf("it should be a map like {days: 1, hours: 2}")
+	}
+	body := strings.TrimSpace(text[1 : len(text)-1])
+	if body == "" {
+		return "", fmt.Errorf("no key is given")
+	}
+	seen := make(map[string]bool)
+	var fields []string
+	for _, field := range strings.Split(body, ",") {
+		kv := strings.SplitN(field, ":", 2)
+		if len(kv) != 2 {
+			return "", fmt.Errorf("%q is not a key: value pair", strings.TrimSpace(field))
+		}
+		key := strings.ToLower(strings.Trim(strings.TrimSpace(kv[0]), "\"'`"))
+		if !durationKeys[key] {
+			return "", fmt.Errorf("unknown key %q", strings.TrimSpace(kv[0]))
+		}
+		if seen[key] {
+			return "", fmt.Errorf("duplicated key %s", key)
+		}
+		seen[key] = true
+		i, err := strconv.ParseInt(strings.TrimSpace(kv[1]), 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("value of %s is not an integer", key)
+		}
+		fields = append(fields, fmt.Sprintf("%s: %d", key, i))
+	}
+	return fmt.Sprintf("duration({%s})", strings.Join(fields, ", ")), nil
+}
@@ -0,0 +1,82 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package importer
+
+import (
+	"testing"
+)
+
+func TestVidLiteral(t *testing.T) {
+	cases := []struct {
+		vidType string
+		text    string
+		want    string
+		wantErr bool
+	}{
+		{"INT64", "100", "100", false},
+		{"INT64", " 100 ", "100", false},
+		{"INT64", "player100", "", true},
+		{"FIXED_STRING(32)", "player100", `"player100"`, false},
+		{"FIXED_STRING(32)", `a"b`, `"a\"b"`, false},
+		{"FIXED_STRING(32)", "", "", true},
+	}
+	for _, c := range cases {
+		got, err := VidLiteral(c.vidType, c.text)
+		if (err != nil) != c.wantErr || got != c.want {
+			t.Errorf("VidLiteral(%q, %q) = %q, %v, want %q, error %t", c.vidType, c.text, got, err, c.want, c.wantErr)
+		}
+	}
+}
+
+func TestLiteral(t *testing.T) {
+	cases := []struct {
+		typ      string
+		nullable bool
+		text     string
+		want     string
+		wantErr  bool
+	}{
+		{"string", false, "Tim Duncan", `"Tim Duncan"`, false},
+		{"fixed_string(10)", false, "", `""`, false},
+		{"int64", false, " 42 ", "42", false},
+		{"int32", false, "4.2", "", true},
+		{"int64", true, "", "NULL", false},
+		{"int64", true, "null", "NULL", false},
+		{"int64", false, "", "", true},
+		{"int64", false, "0x10", "", true},
+		{"double", false, "1.5e3", "1500.0", false},
+		{"double", false, "-0.25", "-0.25", false},
+		{"float", false, "abc", "", true},
+		{"float", false, "NaN", "", true},
+		{"bool", false, "TRUE", "true", false},
+		{"bool", false, "yes", "", true},
+		{"timestamp", false, "1577836800", "1577836800", false},
+		{"timestamp", false, "2020-01-01 00:00:00", `timestamp("2020-01-01 00:00:00")`, false},
+		{"timestamp", false, "yesterday", "", true},
+		{"date", false, "2020-01-01", `date("2020-01-01")`, false},
+		{"date", false, "2020/01/01", "", true},
+		{"time", false, "12:30:15.5", `time("12:30:15.5")`, false},
+		{"datetime", false, "2020-01-01 12:30", `datetime("2020-01-01T12:30")`, false},
+		{"geography(point)", false, "POINT(1 2)", `ST_GeogFromText("POINT(1 2)")`, false},
+		{"duration", false, "{days: 1}", "duration({days: 1})", false},
+		{"duration", false, `{"Days": 1,hours:-2 }`, "duration({days: 1, hours: -2})", false},
+		{"duration", false, "1d", "", true},
+		{"duration", false, "{}", "", true},
+		{"duration", false, "{days: 1, days: 2}", "", true},
+		{"duration", false, "{days: 1.5}", "", true},
+		// The text is never pasted into the statement
+		{"duration", false, "{}) ; DROP SPACE x ; (", "", true},
+		{"duration", false, "{days: 1}) ; DROP SPACE x ; ({days: 1}", "", true},
+		{"list", false, "[1]", "", true},
+	}
+	for _, c := range cases {
+		prop := &Property{Name: "p", Type: c.typ, Nullable: c.nullable}
+		got, err := Literal(prop, c.text)
+		if (err != nil) != c.wantErr || got != c.want {
+			t.Errorf("Literal(%s, %q) = %q, %v, want %q, error %t", c.typ, c.text, got, err, c.want, c.wantErr)
+		}
+	}
+}
@@ -0,0 +1,351 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package importer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+)
+
+const (
+	DefaultBatchSize   = 100
+	DefaultConcurrency = 4
+	// The console connection pool holds at most 16 connections
+	MaxConcurrency = 8
+)
+
+// Row is one input record converted to the values part of an INSERT statement,
+// i.e. `"player100":("Tim Duncan", 42)` or `"player100"->"team204"@0:(1997, 2016)`.
+// A JSON line may be converted to several rows by the mappings.
+type Row struct {
+	// line number in the input file
+	Line int
+	// original fields, written to the reject file
+	Raw   []string
+	Value string
+}
+
+type Options struct {
+	Space       string
+	BatchSize   int
+	Concurrency int
+	RejectFile  string
+	NewSession  func() (*nebula.Session, error)
+	// Session executes the batches one by one instead of the sessions created by NewSession
+	Session *nebula.Session
+	// DryRun prints the statements instead of executing them
+	DryRun bool
+	// Audit is called with the result of every INSERT statement executed, i.e. to write the audit log
+	Audit func(stmt string, res *nebula.ResultSet, err error)
+}
+
+// Stats counts the input records, which are the rows of a CSV file or the lines of a JSON Lines file
+type Stats struct {
+	Total    int64
+	Imported int64
+	Rejected int64
+	Duration time.Duration
+}
+
+func (s Stats) String() string {
+	return fmt.Sprintf("%d records imported, %d records rejected (time spent %v)", s.Imported, s.Rejected, s.Duration)
+}
+
+// rejectWriter writes the rejected rows and the reasons to a csv file,
+// the file is created when the first row is rejected
+type rejectWriter struct {
+	mu       sync.Mutex
+	filename string
+	fd       *os.File
+	writer   *csv.Writer
+}
+
+func (r *rejectWriter) write(row Row, reason error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.filename == "" {
+		return
+	}
+	if r.writer == nil {
+		fd, err := os.OpenFile(r.filename, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+		if err != nil {
+			fmt.Printf("Open or Create file %s failed, %s\n", r.filename, err.Error())
+			r.filename = ""
+			return
+		}
+		r.fd = fd
+		r.writer = csv.NewWriter(fd)
+	}
+	record := append([]string{}, row.Raw...)
+	record = append(record, fmt.Sprintf("line %d: %s", row.Line, reason.Error()))
+	r.writer.Write(record)
+}
+
+func (r *rejectWriter) close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.writer == nil {
+		return
+	}
+	r.writer.Flush()
+	r.fd.Close()
+	r.writer = nil
+}
+
+// record is the rows converted from one input record and the headers of their statements,
+// it's imported or rejected as a whole
+type record struct {
+	headers []string
+	rows    []Row
+}
+
+// batch is the records inserted by one statement of every header
+type batch []record
+
+// Loader executes the rows in batched INSERT statements on several sessions in parallel
+type Loader struct {
+	opts     Options
+	sessions []*nebula.Session
+	// the pending records, and the number of their rows
+	pending     batch
+	pendingRows int
+	batches     chan batch
+	wg          sync.WaitGroup
+	rejects     *rejectWriter
+	stop        chan struct{}
+	start       time.Time
+
+	total    int64
+	imported int64
+	rejected int64
+}
+
+// NewLoader creates the sessions used by the workers, the batches are executed in
+// the current goroutine if a session is given or in dry run mode
+func NewLoader(opts Options) (*Loader, error) {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = DefaultBatchSize
+	}
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = DefaultConcurrency
+	}
+	l := &Loader{
+		opts:    opts,
+		batches: make(chan batch, opts.Concurrency),
+		rejects: &rejectWriter{filename: opts.RejectFile},
+		stop:    make(chan struct{}),
+		start:   time.Now(),
+	}
+	if l.sequential() {
+		if !opts.DryRun {
+			go l.report()
+		}
+		return l, nil
+	}
+	for i := 0; i < opts.Concurrency; i++ {
+		session, err := opts.NewSession()
+		if err != nil {
+			l.release()
+			return nil, err
+		}
+		l.sessions = append(l.sessions, session)
+		if _, err := execute(session, fmt.Sprintf("USE `%s`", opts.Space)); err != nil {
+			l.release()
+			return nil, err
+		}
+	}
+	for _, session := range l.sessions {
+		l.wg.Add(1)
+		go l.work(session)
+	}
+	go l.report()
+	return l, nil
+}
+
+func (l *Loader) sequential() bool {
+	return l.opts.DryRun || l.opts.Session != nil
+}
+
+func (l *Loader) release() {
+	for _, session := range l.sessions {
+		session.Release()
+	}
+	l.sessions = nil
+}
+
+// Add queues the row, a batch is sent to the workers when it is full.
+// The header is the statement before the values, i.e. "INSERT VERTEX `player`(`name`, `age`) VALUES "
+func (l *Loader) Add(header string, row Row) {
+	l.AddLine([]string{header}, []Row{row})
+}
+
+// AddLine queues the rows converted from one input line by several mappings, i.e. a vertex and
+// its edges, the line is counted once, and it's rejected once if any of its rows fails
+func (l *Loader) AddLine(headers []string, rows []Row) {
+	atomic.AddInt64(&l.total, 1)
+	l.pending = append(l.pending, record{headers: headers, rows: rows})
+	l.pendingRows += len(rows)
+	if l.pendingRows >= l.opts.BatchSize {
+		l.send(l.pending)
+		l.pending, l.pendingRows = nil, 0
+	}
+}
+
+func (l *Loader) send(b batch) {
+	if l.sequential() {
+		l.execute(l.opts.Session, b)
+		return
+	}
+	l.batches <- b
+}
+
+// Reject records a row which can not be converted
+func (l *Loader) Reject(row Row, reason error) {
+	atomic.AddInt64(&l.total, 1)
+	atomic.AddInt64(&l.rejected, 1)
+	l.rejects.write(row, reason)
+}
+
+func statement(header string, rows []Row) string {
+	values := make([]string, 0, len(rows))
+	for _, row := range rows {
+		values = append(values, row.Value)
+	}
+	return header + strings.Join(values, ", ")
+}
+
+// group returns the rows of the records by the headers, in the order the headers appear
+func (b batch) group() ([]string, map[string][]Row) {
+	var headers []string
+	rows := make(map[string][]Row)
+	for _, r := range b {
+		for i, header := range r.headers {
+			if _, ok := rows[header]; !ok {
+				headers = append(headers, header)
+			}
+			rows[header] = append(rows[header], r.rows[i])
+		}
+	}
+	return headers, rows
+}
+
+// insert executes the statement, and passes the result to the Audit hook
+func (l *Loader) insert(session *nebula.Session, stmt string) error {
+	res, err := session.Execute(stmt)
+	if l.opts.Audit != nil {
+		l.opts.Audit(stmt, res, err)
+	}
+	if err == nil && !res.IsSucceed() {
+		err = fmt.Errorf("an error occurred when executing: %s, [ERROR (%d)]: %s", stmt, res.GetErrorCode(), res.GetErrorMsg())
+	}
+	return err
+}
+
+// execute inserts the rows of the batch by one statement of every header. The records having
+// rows in the failed statements are retried one by one to find out the bad ones, a record is
+// rejected once if any of its rows fails. Its rows inserted by the other statements are kept,
+// since nGQL has no transactions.
+func (l *Loader) execute(session *nebula.Session, b batch) {
+	headers, rows := b.group()
+	if l.opts.DryRun {
+		for _, header := range headers {
+			fmt.Println(statement(header, rows[header]) + ";")
+		}
+		atomic.AddInt64(&l.imported, int64(len(b)))
+		return
+	}
+	failed := make(map[string]error)
+	for _, header := range headers {
+		if err := l.insert(session, statement(header, rows[header])); err != nil {
+			failed[header] = err
+		}
+	}
+	for _, r := range b {
+		if err := l.retry(session, r, failed, len(b) == 1); err != nil {
+			atomic.AddInt64(&l.rejected, 1)
+			l.rejects.write(r.rows[0], err)
+		} else {
+			atomic.AddInt64(&l.imported, 1)
+		}
+	}
+}
+
+// retry inserts the rows of the record in the failed statements, the error of the statement is
+// returned without retrying if the record is the only one in the batch
+func (l *Loader) retry(session *nebula.Session, r record, failed map[string]error, only bool) error {
+	for i, header := range r.headers {
+		err, ok := failed[header]
+		if !ok {
+			continue
+		}
+		if only {
+			return err
+		}
+		if err := l.insert(session, statement(header, []Row{r.rows[i]})); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (l *Loader) work(session *nebula.Session) {
+	defer l.wg.Done()
+	for b := range l.batches {
+		l.execute(session, b)
+	}
+}
+
+func (l *Loader) printProgress() {
+	imported := atomic.LoadInt64(&l.imported)
+	rejected := atomic.LoadInt64(&l.rejected)
+	elapsed := time.Since(l.start)
+	rate := float64(imported) / elapsed.Seconds()
+	fmt.Printf("\rImported %d rows, rejected %d rows, %.0f rows/s, elapsed %v",
+		imported, rejected, rate, elapsed.Truncate(time.Second))
+}
+
+func (l *Loader) report() {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			l.printProgress()
+		case <-l.stop:
+			return
+		}
+	}
+}
+
+// Close flushes the last batch, waits for the workers and releases the sessions
+func (l *Loader) Close() Stats {
+	if len(l.pending) > 0 {
+		l.send(l.pending)
+		l.pending, l.pendingRows = nil, 0
+	}
+	close(l.batches)
+	l.wg.Wait()
+	close(l.stop)
+	if !l.opts.DryRun {
+		l.printProgress()
+		fmt.Println()
+	}
+	l.release()
+	l.rejects.close()
+	return Stats{
+		Total:    atomic.LoadInt64(&l.total),
+		Imported: atomic.LoadInt64(&l.imported),
+		Rejected: atomic.LoadInt64(&l.rejected),
+		Duration: time.Since(l.start),
+	}
+}
@@ -0,0 +1,176 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package importer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PropMapping maps an input field to a property
+type PropMapping struct {
+	Prop  string `json:"prop"`
+	Field string `json:"field"`
+}
+
+// Mapping maps the input fields to the vid (or src, dst and rank) and the
+// properties of a tag or an edge type
+type Mapping struct {
+	Kind  string        `json:"kind"`
+	Name  string        `json:"name"`
+	Vid   string        `json:"vid,omitempty"`
+	Src   string        `json:"src,omitempty"`
+	Dst   string        `json:"dst,omitempty"`
+	Rank  string        `json:"rank,omitempty"`
+	Props []PropMapping `json:"props"`
+}
+
+// parseMappingList parses the list like `vid=col0, name=col1, age=col2`
+func parseMappingList(kind, name, list string) (*Mapping, error) {
+	m := &Mapping{Kind: strings.ToUpper(kind), Name: name}
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		kv := strings.SplitN(item, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid mapping %s, it should be like prop=col0", item)
+		}
+		key, field := strings.Trim(strings.TrimSpace(kv[0]), "`"), strings.TrimSpace(kv[1])
+		switch strings.ToLower(key) {
+		case "vid":
+			m.Vid = field
+		case "src":
+			m.Src = field
+		case "dst":
+			m.Dst = field
+		case "rank":
+			m.Rank = field
+		default:
+			m.Props = append(m.Props, PropMapping{Prop: key, Field: field})
+		}
+	}
+	return m, nil
+}
+
+// Validate checks the mapping against the schema fetched from the server
+func (m *Mapping) Validate(schema *Schema) error {
+	switch m.Kind {
+	case KindTag:
+		if m.Vid == "" {
+			return fmt.Errorf("vid is not mapped for tag %s", m.Name)
+		}
+	case KindEdge:
+		if m.Src == "" || m.Dst == "" {
+			return fmt.Errorf("src and dst are not mapped for edge %s", m.Name)
+		}
+	default:
+		return fmt.Errorf("unknown kind %s, it should be TAG or EDGE", m.Kind)
+	}
+	for _, p := range m.Props {
+		if _, ok := schema.Prop(p.Prop); !ok {
+			return fmt.Errorf("property %s not found in %s %s", p.Prop, strings.ToLower(m.Kind), m.Name)
+		}
+	}
+	// The properties not mapped are NULL or their default values
+	for _, p := range schema.Props {
+		if p.Nullable || p.HasDefault {
+			continue
+		}
+		found := false
+		for _, mp := range m.Props {
+			found = found || mp.Prop == p.Name
+		}
+		if !found {
+			return fmt.Errorf("property %s is not nullable, has no default value and is not mapped", p.Name)
+		}
+	}
+	return nil
+}
+
+// InsertHeader returns the INSERT statement before the values
+func (m *Mapping) InsertHeader() string {
+	props := make([]string, 0, len(m.Props))
+	for _, p := range m.Props {
+		props = append(props, fmt.Sprintf("`%s`", p.Prop))
+	}
+	kind := "VERTEX"
+	if m.Kind == KindEdge {
+		kind = "EDGE"
+	}
+	return fmt.Sprintf("INSERT %s `%s`(%s) VALUES ", kind, m.Name, strings.Join(props, ", "))
+}
+
+// BuildValue converts the fields of a record to the values part of the INSERT statement,
+// lookup returns the text of a field and whether it is present
+func (m *Mapping) BuildValue(schema *Schema, lookup func(field string) (string, bool)) (string, error) {
+	get := func(field string) (string, error) {
+		s, ok := lookup(field)
+		if !ok {
+			return "", fmt.Errorf("field %s not found", field)
+		}
+		return s, nil
+	}
+
+	var values []string
+	for _, p := range m.Props {
+		prop, _ := schema.Prop(p.Prop)
+		text, ok := lookup(p.Field)
+		if !ok {
+			if !prop.Nullable {
+				return "", fmt.Errorf("field %s not found", p.Field)
+			}
+			values = append(values, "NULL")
+			continue
+		}
+		v, err := Literal(prop, text)
+		if err != nil {
+			return "", err
+		}
+		values = append(values, v)
+	}
+	props := strings.Join(values, ", ")
+
+	if m.Kind == KindTag {
+		text, err := get(m.Vid)
+		if err != nil {
+			return "", err
+		}
+		vid, err := VidLiteral(schema.VidType, text)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s:(%s)", vid, props), nil
+	}
+
+	text, err := get(m.Src)
+	if err != nil {
+		return "", err
+	}
+	src, err := VidLiteral(schema.VidType, text)
+	if err != nil {
+		return "", err
+	}
+	if text, err = get(m.Dst); err != nil {
+		return "", err
+	}
+	dst, err := VidLiteral(schema.VidType, text)
+	if err != nil {
+		return "", err
+	}
+	rank := "0"
+	if m.Rank != "" {
+		if text, err = get(m.Rank); err != nil {
+			return "", err
+		}
+		rank, err = Literal(&Property{Name: "rank", Type: "int64"}, text)
+		if err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("%s->%s@%s:(%s)", src, dst, rank, props), nil
+}
@@ -0,0 +1,101 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package importer
+
+import (
+	"strings"
+	"testing"
+)
+
+var playerSchema = &Schema{Kind: KindTag, Name: "player", VidType: "FIXED_STRING(32)", Props: []Property{
+	{Name: "name", Type: "string"},
+	{Name: "age", Type: "int64", Nullable: true},
+}}
+
+var followSchema = &Schema{Kind: KindEdge, Name: "follow", VidType: "INT64", Props: []Property{
+	{Name: "degree", Type: "int64"},
+}}
+
+// fields looks up the fields of a record in the map
+func fields(record map[string]string) func(string) (string, bool) {
+	return func(field string) (string, bool) {
+		s, ok := record[field]
+		return s, ok
+	}
+}
+
+func TestValidate(t *testing.T) {
+	cases := []struct {
+		mapping *Mapping
+		schema  *Schema
+		wantErr string
+	}{
+		{&Mapping{Kind: KindTag, Name: "player", Vid: "id", Props: []PropMapping{{Prop: "name", Field: "name"}}}, playerSchema, ""},
+		{&Mapping{Kind: KindTag, Name: "player", Props: []PropMapping{{Prop: "name", Field: "name"}}}, playerSchema, "vid is not mapped"},
+		{&Mapping{Kind: KindEdge, Name: "follow", Src: "a", Props: []PropMapping{{Prop: "degree", Field: "d"}}}, followSchema, "src and dst are not mapped"},
+		{&Mapping{Kind: "INDEX", Name: "player"}, playerSchema, "unknown kind"},
+		{&Mapping{Kind: KindTag, Name: "player", Vid: "id", Props: []PropMapping{{Prop: "name", Field: "name"}, {Prop: "height", Field: "h"}}}, playerSchema, "property height not found"},
+		// The nullable properties may be left unmapped, the others must be mapped
+		{&Mapping{Kind: KindTag, Name: "player", Vid: "id", Props: []PropMapping{{Prop: "age", Field: "age"}}}, playerSchema, "property name is not nullable"},
+	}
+	for _, c := range cases {
+		err := c.mapping.Validate(c.schema)
+		if c.wantErr == "" && err != nil || c.wantErr != "" && (err == nil || !strings.Contains(err.Error(), c.wantErr)) {
+			t.Errorf("Validate(%+v) = %v, want %q", c.mapping, err, c.wantErr)
+		}
+	}
+}
+
+func TestValidateDefault(t *testing.T) {
+	schema := &Schema{Kind: KindTag, Name: "player", Props: []Property{
+		{Name: "name", Type: "string"},
+		{Name: "nick", Type: "string", HasDefault: true},
+	}}
+	m := &Mapping{Kind: KindTag, Name: "player", Vid: "id", Props: []PropMapping{{Prop: "name", Field: "name"}}}
+	// An empty default DEFAULT "" is a default value
+	if err := m.Validate(schema); err != nil {
+		t.Errorf("Validate with the default \"\" failed, %s", err)
+	}
+	schema.Props[1].HasDefault = false
+	if err := m.Validate(schema); err == nil {
+		t.Errorf("Validate succeeded without mapping the property having no default value")
+	}
+}
+
+func TestBuildValue(t *testing.T) {
+	player := &Mapping{Kind: KindTag, Name: "player", Vid: "id", Props: []PropMapping{{Prop: "name", Field: "name"}, {Prop: "age", Field: "age"}}}
+	follow := &Mapping{Kind: KindEdge, Name: "follow", Src: "from", Dst: "to", Rank: "rank", Props: []PropMapping{{Prop: "degree", Field: "degree"}}}
+	cases := []struct {
+		mapping *Mapping
+		schema  *Schema
+		record  map[string]string
+		want    string
+		wantErr bool
+	}{
+		{player, playerSchema, map[string]string{"id": "player100", "name": `Tim "TD" Duncan`, "age": "42"}, `"player100":("Tim \"TD\" Duncan", 42)`, false},
+		// The nullable property absent from the record is NULL
+		{player, playerSchema, map[string]string{"id": "player100", "name": "Tim"}, `"player100":("Tim", NULL)`, false},
+		{player, playerSchema, map[string]string{"id": "player100", "age": "42"}, "", true},
+		{player, playerSchema, map[string]string{"name": "Tim"}, "", true},
+		{player, playerSchema, map[string]string{"id": "player100", "name": "Tim", "age": "old"}, "", true},
+		{follow, followSchema, map[string]string{"from": "100", "to": "101", "rank": "1", "degree": "95"}, "100->101@1:(95)", false},
+		{follow, followSchema, map[string]string{"from": "a", "to": "101", "rank": "1", "degree": "95"}, "", true},
+		{follow, followSchema, map[string]string{"from": "100", "to": "101", "degree": "95"}, "", true},
+	}
+	for _, c := range cases {
+		got, err := c.mapping.BuildValue(c.schema, fields(c.record))
+		if (err != nil) != c.wantErr || got != c.want {
+			t.Errorf("BuildValue(%s, %v) = %q, %v, want %q, error %t", c.mapping.Name, c.record, got, err, c.want, c.wantErr)
+		}
+	}
+
+	if got, want := player.InsertHeader(), "INSERT VERTEX `player`(`name`, `age`) VALUES "; got != want {
+		t.Errorf("InsertHeader = %q, want %q", got, want)
+	}
+	if got, want := follow.InsertHeader(), "INSERT EDGE `follow`(`degree`) VALUES "; got != want {
+		t.Errorf("InsertHeader = %q, want %q", got, want)
+	}
+}
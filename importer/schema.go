@@ -0,0 +1,129 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package importer
+
+import (
+	"fmt"
+	"strings"
+
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+)
+
+const (
+	KindTag  = "TAG"
+	KindEdge = "EDGE"
+)
+
+// Property is a property of a tag or an edge type
+type Property struct {
+	Name     string
+	Type     string
+	Nullable bool
+	// Default is the expression of the default value if HasDefault,
+	// which tells an empty default apart from a missing one
+	Default    string
+	HasDefault bool
+}
+
+// Schema is the definition of a tag or an edge type fetched by DESCRIBE
+type Schema struct {
+	Kind    string
+	Name    string
+	VidType string
+	Props   []Property
+}
+
+func (s *Schema) Prop(name string) (*Property, bool) {
+	for i := range s.Props {
+		if s.Props[i].Name == name {
+			return &s.Props[i], true
+		}
+	}
+	return nil, false
+}
+
+func execute(session *nebula.Session, stmt string) (*nebula.ResultSet, error) {
+	res, err := session.Execute(stmt)
+	if err != nil {
+		return nil, err
+	}
+	if !res.IsSucceed() {
+		return nil, fmt.Errorf("an error occurred when executing: %s, [ERROR (%d)]: %s", stmt, res.GetErrorCode(), res.GetErrorMsg())
+	}
+	return res, nil
+}
+
+func stringValue(record *nebula.Record, col string) string {
+	val, err := record.GetValueByColName(col)
+	if err != nil {
+		return ""
+	}
+	if s, err := val.AsString(); err == nil {
+		return s
+	}
+	return val.String()
+}
+
+// defaultValue returns the Default column of DESCRIBE and whether there is a default value,
+// which is NULL or EMPTY if there isn't one while an empty string is the default ""
+func defaultValue(record *nebula.Record) (string, bool) {
+	val, err := record.GetValueByColName("Default")
+	if err != nil || val.IsNull() || val.IsEmpty() {
+		return "", false
+	}
+	if s, err := val.AsString(); err == nil {
+		return s, true
+	}
+	return val.String(), true
+}
+
+// DescribeVidType returns the vid type of the space, i.e. INT64 or FIXED_STRING(32)
+func DescribeVidType(session *nebula.Session, space string) (string, error) {
+	res, err := execute(session, fmt.Sprintf("DESCRIBE SPACE `%s`", space))
+	if err != nil {
+		return "", err
+	}
+	if res.GetRowSize() != 1 {
+		return "", fmt.Errorf("space %s not found", space)
+	}
+	record, err := res.GetRowValuesByIndex(0)
+	if err != nil {
+		return "", err
+	}
+	return stringValue(record, "Vid Type"), nil
+}
+
+// DescribeSchema fetches the properties of the tag or edge type in the space
+func DescribeSchema(session *nebula.Session, space, kind, name string) (*Schema, error) {
+	vidType, err := DescribeVidType(session, space)
+	if err != nil {
+		return nil, err
+	}
+	res, err := execute(session, fmt.Sprintf("DESCRIBE %s `%s`", kind, name))
+	if err != nil {
+		return nil, err
+	}
+	schema := &Schema{
+		Kind:    kind,
+		Name:    name,
+		VidType: vidType,
+	}
+	for i := 0; i < res.GetRowSize(); i++ {
+		record, err := res.GetRowValuesByIndex(i)
+		if err != nil {
+			return nil, err
+		}
+		def, hasDefault := defaultValue(record)
+		schema.Props = append(schema.Props, Property{
+			Name:       stringValue(record, "Field"),
+			Type:       strings.ToLower(stringValue(record, "Type")),
+			Nullable:   strings.EqualFold(stringValue(record, "Null"), "YES"),
+			Default:    def,
+			HasDefault: hasDefault,
+		})
+	}
+	return schema, nil
+}
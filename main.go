@@ -6,25 +6,38 @@
 package main
 
 import (
+	"bytes"
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"io/ioutil"
 	"log"
 	"os"
+	"os/signal"
 	"path"
 	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"text/tabwriter"
 	"time"
 
 	"github.com/manifoldco/promptui"
-	"github.com/vesoft-inc/nebula-console/box"
+	"github.com/vesoft-inc/nebula-console/audit"
 	"github.com/vesoft-inc/nebula-console/cli"
+	"github.com/vesoft-inc/nebula-console/dataset"
+	"github.com/vesoft-inc/nebula-console/dumper"
+	"github.com/vesoft-inc/nebula-console/history"
+	"github.com/vesoft-inc/nebula-console/importer"
+	"github.com/vesoft-inc/nebula-console/migration"
+	"github.com/vesoft-inc/nebula-console/ngql"
 	"github.com/vesoft-inc/nebula-console/printer"
+	"github.com/vesoft-inc/nebula-console/profiles"
+	"github.com/vesoft-inc/nebula-console/top"
+	"github.com/vesoft-inc/nebula-console/waiter"
 	nebulago "github.com/vesoft-inc/nebula-go/v3"
 )
 
@@ -39,6 +52,17 @@ const (
 	Repeat              = 5
 	Param               = 6
 	Params              = 7
+	Export              = 8
+	Import              = 9
+	Dump                = 10
+	Source              = 11
+	SchemaDiff          = 12
+	Wait                = 13
+	Watch               = 14
+	Top                 = 15
+	Timeout             = 16
+	History             = 17
+	Edit                = 18
 )
 
 type ParameterMap map[string]interface{}
@@ -53,6 +77,34 @@ var planDescPrinter = printer.NewPlanDescPrinter()
 // in order to get the total and avearge execution time of the statement"
 var g_repeats = 1
 
+// The audit log of the executed statements and console commands, if enabled by -audit-log
+var g_auditLogger *audit.Logger
+
+// The history of the connection profile in the interactive mode
+var g_history *history.History
+
+// Whether to execute the destructive statements without confirmation, by -yes or the profile settings
+var g_assumeYes bool
+
+// The prompt of the interactive mode, which shows the latency of the last statement
+var g_prompt *cli.Prompt
+
+// The statement to execute instead of reading the input, i.e. rerun from the history
+var g_pendingLine string
+
+// The deadline of every statement, the query is killed when it expires, 0 means never timeout
+var g_statementTimeout time.Duration
+
+// The progress of the dataset loaded by :play
+var g_playProgress *playProgress
+
+// The statements to resume from of the datasets failed to load, by dataset name
+var g_playResumes = make(map[string]playResume)
+
+// The schema objects created by the script being played since the last `:sleep`,
+// which is replaced by waiting for them to be ready
+var g_pendingObjects []waiter.Object
+
 func welcome(interactive bool) {
 	if !interactive {
 		return
@@ -76,23 +128,386 @@ func printConsoleResp(msg string) {
 	fmt.Println()
 }
 
-func playData(data string) (string, error) {
-	boxfilePath := "/" + data + ".ngql"
-	posixfilePath := "./data/" + data + ".ngql"
-	var c cli.Cli
-	// First find it in directory ./data/. If not found, then find it in the embeded box
-	if fd, err := os.Open(posixfilePath); err == nil {
-		c = cli.NewnCli(fd, false, "", func() { fd.Close() })
-	} else if box.Has(boxfilePath) {
-		fileStr := string(box.Get(boxfilePath))
-		c = cli.NewnCli(strings.NewReader(fileStr), false, "", nil)
+// playResume is where to resume loading a dataset, the space is used before the statement
+type playResume struct {
+	statement int
+	line      int
+	space     string
+}
+
+// playProgress prints a progress bar of the statements executed
+type playProgress struct {
+	total   int
+	resumed int
+	start   time.Time
+	printed time.Time
+}
+
+func newPlayProgress(total, resumed int) *playProgress {
+	return &playProgress{total: total, resumed: resumed, start: time.Now()}
+}
+
+func (p *playProgress) update(done int, force bool) {
+	if !force && time.Since(p.printed) < 100*time.Millisecond {
+		return
+	}
+	p.printed = time.Now()
+	const width = 30
+	filled := 0
+	if p.total > 0 {
+		filled = done * width / p.total
+	}
+	elapsed := time.Since(p.start)
+	eta := "-"
+	if executed := done - p.resumed; executed > 0 {
+		eta = (elapsed / time.Duration(executed) * time.Duration(p.total-done)).Truncate(time.Second).String()
+	}
+	fmt.Printf("\r[%s%s] %d/%d statements, elapsed %v, ETA %s ",
+		strings.Repeat("=", filled), strings.Repeat(" ", width-filled), done, p.total, elapsed.Truncate(time.Second), eta)
+}
+
+func playData(data string, resume bool) (string, error) {
+	registry, err := dataset.Load()
+	if err != nil {
+		return "", err
+	}
+	d, ok := registry.Get(data)
+	if !ok {
+		return "", fmt.Errorf("dataset %s not found, see `:play list' for the available datasets", data)
+	}
+	fd, err := d.Open()
+	if err != nil {
+		return "", err
+	}
+	content, err := ioutil.ReadAll(fd)
+	fd.Close()
+	if err != nil {
+		return "", err
+	}
+	total, err := cli.CountStatements(bytes.NewReader(content))
+	if err != nil {
+		return "", err
+	}
+	c := cli.NewnCli(bytes.NewReader(content), false, "", nil)
+
+	done := 0
+	if resume {
+		r, ok := g_playResumes[data]
+		if !ok {
+			return "", fmt.Errorf("no failed loading of dataset %s to resume", data)
+		}
+		// Skip the statements executed, and use the space of the failed statement
+		for done < r.statement-1 {
+			if _, exit, err := c.ReadLine(); err != nil || exit {
+				return "", fmt.Errorf("statement %d not found in dataset %s", r.statement, data)
+			}
+			done, _ = c.Position()
+		}
+		if r.space != "(none)" {
+			res, err := session.Execute(fmt.Sprintf("USE `%s`", r.space))
+			if err != nil {
+				return "", err
+			}
+			if !res.IsSucceed() {
+				return "", fmt.Errorf("use space %s failed, %s", r.space, res.GetErrorMsg())
+			}
+			c.SetSpace(r.space)
+		}
+		fmt.Printf("Resume loading dataset %s from statement %d at line %d...\n", data, r.statement, r.line)
 	} else {
-		return "", fmt.Errorf("file %s.ngql not existed in embed box and file directory ./data/ ", data)
+		fmt.Printf("Start loading dataset %s...\n", data)
+	}
+
+	g_playProgress = newPlayProgress(total, done)
+	g_pendingObjects = nil
+	space, err := playScript(c)
+	if err != nil {
+		fmt.Println()
+		statement, line := c.Position()
+		g_playProgress, g_pendingObjects = nil, nil
+		g_playResumes[data] = playResume{statement: statement, line: line, space: c.GetSpace()}
+		return "", fmt.Errorf("statement %d at line %d failed, %s\nFix the problem and use `:play %s --resume' to resume from it",
+			statement, line, err.Error(), data)
+	}
+	g_playProgress.update(total, true)
+	fmt.Println()
+	g_playProgress, g_pendingObjects = nil, nil
+	delete(g_playResumes, data)
+
+	// Verify by another session, so the space of the current session is not changed
+	s, err := newSession()
+	if err != nil {
+		return "", err
+	}
+	defer s.Release()
+	msg, err := d.Verify(s)
+	if err != nil {
+		return "", fmt.Errorf("verify dataset %s failed, %s", data, err.Error())
+	}
+	if msg != "" {
+		fmt.Println(msg)
+	}
+	return space, nil
+}
+
+// waitPendingObjects waits for the schema objects created by the script to be ready
+func waitPendingObjects() {
+	objects := g_pendingObjects
+	g_pendingObjects = nil
+	s, err := newSession()
+	if err != nil {
+		printConsoleResp("Error: " + err.Error())
+		return
+	}
+	defer s.Release()
+	if err := waiter.Wait(s, objects, waiter.DefaultTimeout); err != nil {
+		printConsoleResp("Error: " + err.Error())
+	}
+}
+
+// waitFor polls until the object is usable, or the job is finished:
+// :wait space|tag|edge|index name [timeout] or :wait job id [timeout]
+func waitFor(c cli.Cli, args []string) {
+	if len(args) < 2 {
+		printConsoleResp("Error: wrong wait command, usage: :wait space|tag|edge|index|job name|id [timeout]")
+		return
+	}
+	timeout := waiter.DefaultTimeout
+	if len(args) > 2 {
+		d, err := time.ParseDuration(args[2])
+		if err != nil {
+			printConsoleResp("Error: invalid timeout, " + err.Error())
+			return
+		}
+		timeout = d
+	}
+	kind, name, space := strings.ToUpper(args[0]), strings.Trim(args[1], "`"), c.GetSpace()
+	if kind != waiter.KindSpace && (space == "" || space == "(none)") {
+		printConsoleResp("Error: please choose a graph space with `USE spaceName' first")
+		return
+	}
+
+	s, err := newSession()
+	if err != nil {
+		printConsoleResp("Error: " + err.Error())
+		return
+	}
+	defer s.Release()
+	switch kind {
+	case waiter.KindSpace:
+		err = waiter.Wait(s, []waiter.Object{{Kind: kind, Space: name, Name: name}}, timeout)
+	case waiter.KindTag, waiter.KindEdge, waiter.KindIndex:
+		err = waiter.Wait(s, []waiter.Object{{Kind: kind, Space: space, Name: name}}, timeout)
+	case "JOB":
+		var job int64
+		if job, err = strconv.ParseInt(name, 10, 64); err != nil {
+			printConsoleResp("Error: invalid job id " + name)
+			return
+		}
+		// Jobs are listed in their spaces
+		if res, err := s.Execute(fmt.Sprintf("USE `%s`", space)); err != nil || !res.IsSucceed() {
+			printConsoleResp("Error: use space " + space + " failed")
+			return
+		}
+		err = waiter.WaitJob(s, job, timeout)
+	default:
+		printConsoleResp("Error: unsupported wait " + args[0])
+		return
+	}
+	if err != nil {
+		c.SetRespError(err.Error())
+		printConsoleResp("Error: " + err.Error())
+		return
+	}
+	if c.Output() && kind == "JOB" {
+		printConsoleResp(fmt.Sprintf("Job %s is finished", name))
+	} else if c.Output() {
+		printConsoleResp(fmt.Sprintf("%s %s is ready", strings.ToLower(kind), name))
+	}
+}
+
+var watchRegexp = regexp.MustCompile(`(?is)^:watch\s+(\S+)\s+(?:--until\s+("[^"]*"|'[^']*')\s+)?(.+)$`)
+
+// watch executes the statement on the interval and redraws the result in place like watch(1),
+// until Ctrl-C is pressed or the condition of --until is met
+func watch(c cli.Cli, cmd string) {
+	m := watchRegexp.FindStringSubmatch(cmd)
+	if m == nil {
+		printConsoleResp("Error: wrong watch command, usage: :watch interval [--until \"condition\"] statement")
+		return
+	}
+	interval, err := time.ParseDuration(m[1])
+	if err != nil {
+		// The interval in seconds like :sleep
+		i, err := strconv.Atoi(m[1])
+		if err != nil {
+			printConsoleResp("Error: invalid interval, " + m[1])
+			return
+		}
+		interval = time.Duration(i) * time.Second
+	}
+	if interval <= 0 {
+		printConsoleResp("Error: interval should be greater than 0")
+		return
+	}
+	var until *printer.Condition
+	if m[2] != "" {
+		if until, err = printer.ParseCondition(m[2][1 : len(m[2])-1]); err != nil {
+			printConsoleResp("Error: " + err.Error())
+			return
+		}
+	}
+	stmt := m[3]
+	if err := checkReadOnly(stmt); err != nil {
+		printConsoleResp("Error: " + err.Error())
+		return
+	}
+	// Confirmed once, not on every run
+	if c.Interactive() && !c.IsPlayingData() && !g_assumeYes && !confirmDestructive(stmt, c.GetSpace()) {
+		printConsoleResp("Cancelled")
+		return
+	}
+
+	// Ctrl-C stops watching instead of quitting the console, the running query is killed by executeCancellable
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+
+	watchPrinter := printer.NewWatchPrinter()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		res, stopped, err := executeCancellable(c, stmt)
+		auditLog(c, resultEntry(stmt, res, err))
+		if errors.Is(err, errNotKilled) {
+			printConsoleResp("Error: " + err.Error())
+			return
+		}
+		if stopped == errCancelled {
+			return
+		}
+		// Clear the screen and move the cursor to the top left
+		fmt.Print("\033[H\033[2J")
+		fmt.Printf("Every %v: %s    %s\n\n", interval, stmt, time.Now().Format(time.RFC1123))
+		switch {
+		case err != nil:
+			fmt.Printf("Error: %s\n", err.Error())
+		case stopped != nil:
+			fmt.Printf("Error: %s\n", stopped.Error())
+		case !res.IsSucceed() && !res.IsPartialSucceed():
+			fmt.Printf("[ERROR (%d)]: %s\n", res.GetErrorCode(), res.GetErrorMsg())
+		case res.IsSetData():
+			fmt.Println(watchPrinter.Render(res))
+			fmt.Printf("Got %d rows (time spent %v)\n", res.GetRowSize(), time.Duration(res.GetLatency()*1000))
+		default:
+			fmt.Printf("Execution succeeded (time spent %v)\n", time.Duration(res.GetLatency()*1000))
+		}
+		if err == nil && res.IsSucceed() {
+			c.SetSpace(res.GetSpaceName())
+			if until != nil {
+				matched, err := until.Match(res)
+				if err != nil {
+					printConsoleResp("Error: " + err.Error())
+					return
+				}
+				if matched {
+					printConsoleResp(fmt.Sprintf("Condition %s is met", until.String()))
+					return
+				}
+			}
+		}
+		fmt.Println("Press Ctrl-C to stop watching")
+		select {
+		case <-interrupt:
+			fmt.Println()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// showTop shows the full-screen dashboard of the cluster by the current session,
+// :top [interval]
+func showTop(c cli.Cli, args []string) {
+	if !c.Interactive() {
+		printConsoleResp("Error: :top is only supported in the interactive mode")
+		return
 	}
+	interval := top.DefaultInterval
+	if len(args) > 0 {
+		d, err := time.ParseDuration(args[0])
+		if err != nil {
+			printConsoleResp("Error: invalid interval, " + err.Error())
+			return
+		}
+		interval = d
+	}
+	space := c.GetSpace()
+	if space == "(none)" {
+		space = ""
+	}
+	d := top.NewDashboard(session, space, session.GetSessionID(), interval)
+	d.Killer = auditedSession{c: c, session: session}
+	if err := d.Run(); err != nil {
+		printConsoleResp("Error: " + err.Error())
+	}
+}
+
+// listDatasets prints the datasets which can be loaded by :play
+func listDatasets() {
+	registry, err := dataset.Load()
+	if err != nil {
+		printConsoleResp("Error: " + err.Error())
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Name\tSpace\tVertices\tEdges\tDescription")
+	for _, d := range registry.List() {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%s\n", d.Name, d.Space, d.Vertices, d.Edges, d.Description)
+	}
+	w.Flush()
+	fmt.Println()
+}
 
+// datasetInfo prints the metadata of the dataset
+func datasetInfo(name string) {
+	registry, err := dataset.Load()
+	if err != nil {
+		printConsoleResp("Error: " + err.Error())
+		return
+	}
+	d, ok := registry.Get(name)
+	if !ok {
+		printConsoleResp(fmt.Sprintf("Error: dataset %s not found, see `:play list' for the available datasets", name))
+		return
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%s\n", d.Name)
+	fmt.Fprintf(w, "Description:\t%s\n", d.Description)
+	fmt.Fprintf(w, "Space:\t%s\n", d.Space)
+	fmt.Fprintf(w, "Vid Type:\t%s\n", d.VidType)
+	fmt.Fprintf(w, "Vertices:\t%d\n", d.Vertices)
+	fmt.Fprintf(w, "Edges:\t%d\n", d.Edges)
+	fmt.Fprintf(w, "Source:\t%s\n", d.Source())
+	w.Flush()
+	fmt.Println()
+}
+
+// sourceFile executes the statements in the file, it stops at the first failed statement
+func sourceFile(filename string) (string, error) {
+	fd, err := os.Open(filename)
+	if err != nil {
+		return "", err
+	}
+	c := cli.NewnCli(fd, false, "", func() { fd.Close() })
+	fmt.Printf("Start executing %s...\n", filename)
+	return playScript(c)
+}
+
+func playScript(c cli.Cli) (string, error) {
+	defer c.Close()
 	c.PlayingData(true)
 	defer c.PlayingData(false)
-	fmt.Printf("Start loading dataset %s...\n", data)
 	err := loop(c)
 	if err != nil {
 		return "", err
@@ -104,6 +519,259 @@ func playData(data string) (string, error) {
 	return c.GetSpace(), nil
 }
 
+// migrate runs the subcommand `migrate [status] -dir migrations/ -space name [--dry-run]`,
+// the migrations are executed like the scripts of -f over the session
+func migrate(args []string) error {
+	fs := flag.NewFlagSet("migrate", flag.ExitOnError)
+	dir := fs.String("dir", "migrations", "The directory of the migrations named like V001__init.ngql")
+	space := fs.String("space", "", "The space to migrate, where the applied migrations are recorded")
+	dryRun := fs.Bool("dry-run", false, "Print the pending migrations instead of applying them")
+	status := false
+	if len(args) > 0 && args[0] == "status" {
+		status, args = true, args[1:]
+	}
+	fs.Parse(args)
+	if fs.NArg() == 1 && fs.Arg(0) == "status" {
+		status = true
+	} else if fs.NArg() > 0 {
+		return fmt.Errorf("unknown arguments %s", strings.Join(fs.Args(), " "))
+	}
+	if *space == "" {
+		return fmt.Errorf("the space to migrate is not specified by -space")
+	}
+	if *readOnly && !status && !*dryRun {
+		return fmt.Errorf("migrations can't be applied in the read-only mode, use status or -dry-run")
+	}
+
+	runner := &migration.Runner{
+		Session: session,
+		Space:   *space,
+		Dir:     *dir,
+		DryRun:  *dryRun,
+		Audit: func(stmt string, res *nebulago.ResultSet, err error) {
+			e := resultEntry(stmt, res, err)
+			e.Space = *space
+			auditLog(nil, e)
+		},
+		Execute: func(filename string) error {
+			fd, err := os.Open(filename)
+			if err != nil {
+				return err
+			}
+			_, err = playScript(cli.NewnCli(fd, false, "", func() { fd.Close() }))
+			return err
+		},
+	}
+	if status {
+		return runner.Status()
+	}
+	return runner.Migrate()
+}
+
+// newSession creates another session with the same user, which is used by the commands running in parallel
+func newSession() (*nebulago.Session, error) {
+	return pool.GetSession(*username, *password)
+}
+
+func importData(c cli.Cli, cmd string) {
+	space := c.GetSpace()
+	if space == "" || space == "(none)" {
+		printConsoleResp("Error: please choose a graph space with `USE spaceName' first")
+		return
+	}
+	words := strings.Fields(cmd)
+	if len(words) < 3 {
+		printConsoleResp("Error: wrong import command, usage: :import csv|jsonl file ...")
+		return
+	}
+
+	var (
+		stats      importer.Stats
+		err        error
+		rejectFile string
+	)
+	switch strings.ToLower(words[1]) {
+	case "csv":
+		var imp *importer.CsvImport
+		if imp, err = importer.ParseCsvImport(cmd); err != nil {
+			printConsoleResp("Error: " + err.Error())
+			return
+		}
+		fmt.Printf("Start importing %s into %s %s...\n", imp.File, strings.ToLower(imp.Mapping.Kind), imp.Mapping.Name)
+		imp.Options.Audit = auditHook(c)
+		stats, err = imp.Run(session, space, newSession)
+		rejectFile = imp.Options.RejectFile
+	case "jsonl", "json":
+		var imp *importer.JsonImport
+		if imp, err = importer.ParseJsonImport(cmd); err != nil {
+			printConsoleResp("Error: " + err.Error())
+			return
+		}
+		if !imp.Options.DryRun {
+			fmt.Printf("Start importing %s with mapping %s...\n", imp.File, imp.MappingFile)
+		}
+		imp.Options.Audit = auditHook(c)
+		stats, err = imp.Run(session, space)
+		rejectFile = imp.Options.RejectFile
+	default:
+		printConsoleResp("Error: unsupported import format " + words[1])
+		return
+	}
+	if err != nil {
+		printConsoleResp("Error: import failed, " + err.Error())
+		return
+	}
+	msg := "Import finished, " + stats.String()
+	if stats.Rejected > 0 {
+		msg += fmt.Sprintf(", the rejected rows are written to %s", rejectFile)
+	}
+	printConsoleResp(msg)
+}
+
+// :dump schema|data [space] [> file]
+var dumpRegexp = regexp.MustCompile(`(?i)^\s*:dump\s+(\w+)(\s+[^>\s]+)?\s*(>\s*(\S+))?\s*$`)
+
+func dump(c cli.Cli, cmd string) {
+	matches := dumpRegexp.FindStringSubmatch(cmd)
+	if matches == nil {
+		printConsoleResp("Error: wrong dump command, usage: :dump schema|data [space] [> file]")
+		return
+	}
+	kind, space, filename := strings.ToLower(matches[1]), strings.TrimSpace(matches[2]), matches[4]
+	if space == "" {
+		space = c.GetSpace()
+	}
+	if space == "" || space == "(none)" {
+		printConsoleResp("Error: please specify the space or choose a graph space with `USE spaceName' first")
+		return
+	}
+	if kind != "schema" && kind != "data" {
+		printConsoleResp("Error: unsupported dump " + kind)
+		return
+	}
+
+	// Dump by another session, so the space of the current session is not changed
+	s, err := newSession()
+	if err != nil {
+		printConsoleResp("Error: " + err.Error())
+		return
+	}
+	defer s.Release()
+	schema, err := dumper.FetchSchema(s, space)
+	if err != nil {
+		printConsoleResp("Error: dump schema failed, " + err.Error())
+		return
+	}
+
+	out := os.Stdout
+	if filename != "" {
+		fd, err := os.OpenFile(filename, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+		if err != nil {
+			printConsoleResp(fmt.Sprintf("Error: open or create file %s failed, %s", filename, err.Error()))
+			return
+		}
+		defer fd.Close()
+		out = fd
+	}
+	if kind == "schema" {
+		if err := schema.WriteScript(out); err != nil {
+			printConsoleResp("Error: dump schema failed, " + err.Error())
+			return
+		}
+		if filename != "" {
+			printConsoleResp(fmt.Sprintf("Schema of space %s is dumped to %s", space, filename))
+		}
+		return
+	}
+
+	d := dumper.NewDataDumper(s, schema, out)
+	// The progress is mixed with the statements when they are printed
+	d.Progress = filename != ""
+	stats, err := d.Dump()
+	if err != nil {
+		printConsoleResp("Error: dump data failed, " + err.Error())
+		return
+	}
+	for _, warning := range stats.Warnings {
+		printConsoleResp("Warning: " + warning)
+	}
+	if filename != "" {
+		printConsoleResp(fmt.Sprintf("Data of space %s is dumped to %s, %s", space, filename, stats.String()))
+	}
+}
+
+// loadSchema fetches the schema of the space, or parses the file written by :dump schema
+func loadSchema(s *nebulago.Session, name string) (*dumper.SpaceSchema, error) {
+	if info, err := os.Stat(name); err == nil && !info.IsDir() {
+		fd, err := os.Open(name)
+		if err != nil {
+			return nil, err
+		}
+		defer fd.Close()
+		return dumper.ParseScript(fd)
+	}
+	return dumper.FetchSchema(s, name)
+}
+
+// :schema diff source target [> file]
+var schemaDiffRegexp = regexp.MustCompile(`(?i)^\s*:schema\s+diff\s+(\S+)\s+([^>\s]+)\s*(>\s*(\S+))?\s*$`)
+
+// schemaDiff reports the differences of the target from the source, and writes the
+// statements making the target match the source to the file, or prints them
+func schemaDiff(cmd string) {
+	matches := schemaDiffRegexp.FindStringSubmatch(cmd)
+	if matches == nil {
+		printConsoleResp("Error: wrong schema command, usage: :schema diff spaceA|dumpFileA spaceB|dumpFileB [> file]")
+		return
+	}
+	s, err := newSession()
+	if err != nil {
+		printConsoleResp("Error: " + err.Error())
+		return
+	}
+	defer s.Release()
+	source, err := loadSchema(s, matches[1])
+	if err != nil {
+		printConsoleResp("Error: load schema failed, " + err.Error())
+		return
+	}
+	target, err := loadSchema(s, matches[2])
+	if err != nil {
+		printConsoleResp("Error: load schema failed, " + err.Error())
+		return
+	}
+	diff, err := dumper.DiffSchema(source, target)
+	if err != nil {
+		printConsoleResp("Error: diff schema failed, " + err.Error())
+		return
+	}
+	diff.WriteReport(os.Stdout)
+	if len(diff.Changes) == 0 {
+		return
+	}
+
+	filename := matches[4]
+	out := os.Stdout
+	if filename != "" {
+		fd, err := os.OpenFile(filename, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+		if err != nil {
+			printConsoleResp(fmt.Sprintf("Error: open or create file %s failed, %s", filename, err.Error()))
+			return
+		}
+		defer fd.Close()
+		out = fd
+	} else {
+		fmt.Println()
+	}
+	if err := diff.WriteScript(out); err != nil {
+		printConsoleResp("Error: write migration script failed, " + err.Error())
+		return
+	}
+	if filename != "" {
+		printConsoleResp(fmt.Sprintf("Migration script is written to %s, please review the statements marked DESTRUCTIVE before executing it", filename))
+	}
+}
+
 func defineParams(args string) {
 	argsRewritten := strings.Replace(args, "'", "\"", -1)
 	reg := regexp.MustCompile(`^\s*:param\s+(\S+)\s*=>(.*)$`)
@@ -207,7 +875,42 @@ func isConsoleCmd(cmd string) (isLocal bool, localCmd int, args []string) {
 	case "play":
 		{
 			localCmd = PlayData
-			args = []string{words[1]}
+			args = words[1:]
+		}
+	case "wait":
+		{
+			localCmd = Wait
+			args = words[1:]
+		}
+	case "watch":
+		{
+			localCmd = Watch
+			args = []string{plain}
+		}
+	case "top":
+		{
+			localCmd = Top
+			args = words[1:]
+		}
+	case "source":
+		{
+			localCmd = Source
+			args = words[1:]
+		}
+	case "edit":
+		{
+			localCmd = Edit
+			args = words[1:]
+		}
+	case "history":
+		{
+			localCmd = History
+			args = words[1:]
+		}
+	case "timeout":
+		{
+			localCmd = Timeout
+			args = words[1:]
 		}
 	case "repeat":
 		{
@@ -216,8 +919,28 @@ func isConsoleCmd(cmd string) (isLocal bool, localCmd int, args []string) {
 		}
 	case "csv":
 		{
-			localCmd = ExportCsv
-			args = []string{words[1]}
+			localCmd = ExportCsv
+			args = words[1:]
+		}
+	case "import":
+		{
+			localCmd = Import
+			args = []string{plain}
+		}
+	case "dump":
+		{
+			localCmd = Dump
+			args = []string{plain}
+		}
+	case "schema":
+		{
+			localCmd = SchemaDiff
+			args = []string{plain}
+		}
+	case "export":
+		{
+			localCmd = Export
+			args = words[1:]
 		}
 	case "dot", "profile", "explain":
 		{
@@ -241,23 +964,109 @@ func isConsoleCmd(cmd string) (isLocal bool, localCmd int, args []string) {
 func executeConsoleCmd(c cli.Cli, cmd int, args []string) {
 	switch cmd {
 	case ExportCsv:
-		dataSetPrinter.ExportCsv(args[0])
+		// :csv file [--no-terminal-echo]
+		echo := true
+		filename := ""
+		for _, arg := range args {
+			if arg == "--no-terminal-echo" {
+				echo = false
+			} else {
+				filename = arg
+			}
+		}
+		if filename == "" {
+			printConsoleResp("Error: missing csv file name")
+			return
+		}
+		dataSetPrinter.ExportCsv(filename, echo)
+	case Export:
+		// :export parquet|arrow|graph file
+		if len(args) != 2 {
+			printConsoleResp("Error: wrong export command, usage: :export parquet|arrow|graph file")
+			return
+		}
+		var (
+			exporter printer.ResultExporter
+			err      error
+		)
+		if format := strings.ToLower(args[0]); format == "graph" {
+			exporter, err = printer.NewGraphExporter(args[1])
+		} else {
+			exporter, err = printer.NewArrowExporter(format, args[1])
+		}
+		if err != nil {
+			printConsoleResp("Error: " + err.Error())
+			return
+		}
+		dataSetPrinter.Export(exporter)
+	case Import:
+		if *readOnly {
+			printConsoleResp("Error: :import is rejected in the read-only mode")
+			return
+		}
+		importData(c, args[0])
+	case Dump:
+		dump(c, args[0])
+	case SchemaDiff:
+		schemaDiff(args[0])
 	case ExportExecutionPlan:
 		planDescPrinter.ExportExecutionPlan(args[0])
 	case PlayData:
-		newSpace, err := playData(args[0])
+		// :play list, :play info name or :play name
+		if len(args) == 0 || args[0] == "list" {
+			listDatasets()
+			return
+		}
+		if args[0] == "info" {
+			if len(args) < 2 {
+				printConsoleResp("Error: missing dataset name, usage: :play info name")
+				return
+			}
+			datasetInfo(args[1])
+			return
+		}
+		newSpace, err := playData(args[0], len(args) > 1 && args[1] == "--resume")
 		if err != nil {
 			printConsoleResp("Error: load dataset failed, " + err.Error())
 		} else {
 			printConsoleResp("Load dataset succeeded!")
 			c.SetSpace(newSpace)
 		}
+	case Source:
+		if len(args) < 1 {
+			printConsoleResp("Error: wrong source command, usage: :source file")
+			return
+		}
+		newSpace, err := sourceFile(args[0])
+		if err != nil {
+			printConsoleResp("Error: execute file failed, " + err.Error())
+		} else {
+			printConsoleResp(fmt.Sprintf("Execute file %s succeeded!", args[0]))
+			c.SetSpace(newSpace)
+		}
 	case Sleep:
 		i, err := strconv.Atoi(args[0])
 		if err != nil {
 			printConsoleResp("Error: invalid integer, " + err.Error())
 		}
+		// The heartbeat waits of the script are replaced by waiting for the created schema
+		if c.IsPlayingData() && len(g_pendingObjects) > 0 {
+			waitPendingObjects()
+			return
+		}
 		time.Sleep(time.Duration(i) * time.Second)
+	case Wait:
+		waitFor(c, args)
+	case Watch:
+		watch(c, args[0])
+	case Top:
+		showTop(c, args)
+	case Timeout:
+		setStatementTimeout(args)
+	case History:
+		showHistory(args)
+	case Edit:
+		editStatement(c, args)
 	case Repeat:
 		i, err := strconv.Atoi(args[0])
 		if err != nil {
@@ -281,9 +1090,10 @@ func executeConsoleCmd(c cli.Cli, cmd int, args []string) {
 	}
 }
 
-func printResultSet(res *nebulago.ResultSet, startTime time.Time) (duration time.Duration) {
+// printResultSet prints the result of a statement, the secrets of the statement are masked in the error message
+func printResultSet(res *nebulago.ResultSet, startTime time.Time, secrets []string) (duration time.Duration) {
 	if !res.IsSucceed() && !res.IsPartialSucceed() {
-		fmt.Printf("[ERROR (%d)]: %s", res.GetErrorCode(), res.GetErrorMsg())
+		fmt.Printf("[ERROR (%d)]: %s", res.GetErrorCode(), ngql.RedactMessage(res.GetErrorMsg(), secrets))
 		fmt.Println()
 		fmt.Println()
 		return
@@ -313,7 +1123,7 @@ func printResultSet(res *nebulago.ResultSet, startTime time.Time) (duration time
 		fmt.Printf("[WARNING]: %s", res.GetComment())
 	}
 
-	if res.IsSetPlanDesc() {
+	if res.IsSetPlanDesc() && !planDescPrinter.IsAggregating() {
 		fmt.Println()
 		fmt.Printf("Execution Plan (optimize time %d us)\n", res.GetPlanDesc().GetOptimizeTimeInUs())
 		fmt.Println()
@@ -324,59 +1134,569 @@ func printResultSet(res *nebulago.ResultSet, startTime time.Time) (duration time
 	return
 }
 
+// errRedacted rejects the statements recalled from the history with the credentials masked,
+// which would change the passwords to '***'
+var errRedacted = fmt.Errorf("the password masked as '%s' in the history can not be executed, type it instead", ngql.RedactedSecret)
+
+// recordHistory adds the line typed in the interactive console to the history, the credentials are masked
+func recordHistory(c cli.Cli, line, space string, start time.Time) {
+	if g_history == nil || !c.Interactive() || c.IsPlayingData() {
+		return
+	}
+	if space == "(none)" {
+		space = ""
+	}
+	_, err := g_history.Add(history.Entry{
+		Time:      start,
+		Space:     space,
+		Duration:  time.Since(start),
+		Statement: ngql.Redact(line),
+	})
+	if err != nil {
+		printConsoleResp("Error: write history failed, " + err.Error())
+	}
+}
+
+// showHistory lists the latest entries of the history, searches them, or reruns one,
+// :history [N|grep pattern|rerun id]
+func showHistory(args []string) {
+	if g_history == nil {
+		printConsoleResp("Error: history is only available in the interactive mode")
+		return
+	}
+	entries := g_history.Last(20)
+	if len(args) > 0 {
+		switch strings.ToLower(args[0]) {
+		case "grep":
+			if len(args) < 2 {
+				printConsoleResp("Error: missing pattern, usage: :history grep pattern")
+				return
+			}
+			pattern, err := regexp.Compile("(?i)" + strings.Join(args[1:], " "))
+			if err != nil {
+				printConsoleResp("Error: invalid pattern, " + err.Error())
+				return
+			}
+			entries = g_history.Grep(pattern)
+		case "rerun":
+			id := -1
+			if len(args) == 2 {
+				id, _ = strconv.Atoi(args[1])
+			}
+			e, ok := g_history.Get(id)
+			if !ok {
+				printConsoleResp("Error: history entry not found, usage: :history rerun id")
+				return
+			}
+			if ngql.HasRedacted(e.Statement) {
+				printConsoleResp("Error: " + errRedacted.Error())
+				return
+			}
+			fmt.Println(e.Statement)
+			g_pendingLine = e.Statement
+			return
+		default:
+			n, err := strconv.Atoi(args[0])
+			if err != nil || n < 1 {
+				printConsoleResp("Error: wrong history command, usage: :history [N|grep pattern|rerun id]")
+				return
+			}
+			entries = g_history.Last(n)
+		}
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTime\tSpace\tDuration\tStatement")
+	for _, e := range entries {
+		t := ""
+		if !e.Time.IsZero() {
+			t = e.Time.In(time.Local).Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%v\t%s\n", e.ID, t, e.Space, e.Duration.Round(time.Millisecond), e.Statement)
+	}
+	w.Flush()
+	fmt.Println()
+}
+
+// editStatement opens the previous statement, or the one in the history by ID, in the editor,
+// and executes the text edited, :edit [id]
+func editStatement(c cli.Cli, args []string) {
+	if g_history == nil {
+		printConsoleResp("Error: :edit is only available in the interactive mode")
+		return
+	}
+	text := ""
+	if len(args) > 0 {
+		id, _ := strconv.Atoi(args[0])
+		e, ok := g_history.Get(id)
+		if !ok {
+			printConsoleResp("Error: history entry not found, usage: :edit [id]")
+			return
+		}
+		text = e.Statement
+	} else if last := g_history.Last(1); len(last) > 0 {
+		text = last[0].Statement
+	}
+	edited, err := cli.Edit(text)
+	if err != nil {
+		printConsoleResp("Error: " + err.Error())
+		return
+	}
+	if ngql.HasRedacted(edited) {
+		printConsoleResp("Error: " + errRedacted.Error())
+		return
+	}
+	// The text is split into statements the same as a file
+	n, err := cli.CountStatements(strings.NewReader(edited))
+	if err != nil {
+		printConsoleResp("Error: " + err.Error())
+		return
+	}
+	switch n {
+	case 0:
+		printConsoleResp("Nothing to execute")
+	case 1:
+		// Executed as if it's typed, so it's recorded in the history
+		r := cli.NewnCli(strings.NewReader(edited), false, "", nil)
+		for {
+			line, exit, err := r.ReadLine()
+			if err != nil || exit {
+				return
+			}
+			if line != "" {
+				fmt.Println(line)
+				g_pendingLine = line
+				return
+			}
+		}
+	default:
+		space, err := playScript(cli.NewnCli(strings.NewReader(edited), true, *username, nil))
+		if err != nil {
+			printConsoleResp("Error: execute edited statements failed, " + err.Error())
+			return
+		}
+		c.SetSpace(space)
+	}
+}
+
+// auditLog writes the entry with the user, the graphd host, the space and the parameters
+// used by the statement, if the audit log is enabled. The space of the entry is kept if c is nil.
+func auditLog(c cli.Cli, e audit.Entry) {
+	if g_auditLogger == nil {
+		return
+	}
+	e.Timestamp = time.Now()
+	e.Error = ngql.RedactMessage(e.Error, ngql.Secrets(e.Statement))
+	e.Statement = ngql.Redact(e.Statement)
+	e.User = *username
+	e.Host = fmt.Sprintf("%s:%d", *address, *port)
+	if c != nil && c.GetSpace() != "(none)" {
+		e.Space = c.GetSpace()
+	}
+	if e.Kind == audit.KindStatement {
+		for _, t := range ngql.Tokenize(e.Statement) {
+			if t.Kind != ngql.Parameter {
+				continue
+			}
+			if v, ok := parameterMap[t.Text[1:]]; ok {
+				if e.Params == nil {
+					e.Params = make(map[string]interface{})
+				}
+				e.Params[t.Text[1:]] = v
+			}
+		}
+	}
+	if err := g_auditLogger.Log(e); err != nil {
+		printConsoleResp("Error: write audit log failed, " + err.Error())
+	}
+}
+
+// resultEntry is the audit entry of a statement with its result, or the error if it's not executed
+func resultEntry(stmt string, res *nebulago.ResultSet, err error) audit.Entry {
+	e := audit.Entry{Kind: audit.KindStatement, Statement: stmt}
+	if err != nil {
+		e.Error = err.Error()
+		return e
+	}
+	e.ErrorCode = int(res.GetErrorCode())
+	e.Error = res.GetErrorMsg()
+	e.LatencyUs = res.GetLatency()
+	e.Rows = res.GetRowSize()
+	return e
+}
+
+// auditHook writes the statements executed outside the loop to the audit log, i.e. the batches of :import
+func auditHook(c cli.Cli) func(stmt string, res *nebulago.ResultSet, err error) {
+	return func(stmt string, res *nebulago.ResultSet, err error) {
+		auditLog(c, resultEntry(stmt, res, err))
+	}
+}
+
+// auditedSession executes the statements by the session and writes them to the audit log,
+// i.e. KILL QUERY from Ctrl-C and :top
+type auditedSession struct {
+	c       cli.Cli
+	session *nebulago.Session
+}
+
+func (s auditedSession) Execute(stmt string) (*nebulago.ResultSet, error) {
+	res, err := s.session.Execute(stmt)
+	auditLog(s.c, resultEntry(stmt, res, err))
+	return res, err
+}
+
+// checkReadOnly rejects the statements which don't only read in the read-only mode
+func checkReadOnly(stmt string) error {
+	if !*readOnly {
+		return nil
+	}
+	ok, s := ngql.ReadOnly(stmt)
+	if ok {
+		return nil
+	}
+	if s.Class == ngql.Unknown {
+		return fmt.Errorf("unrecognized statement is rejected in the read-only mode")
+	}
+	return fmt.Errorf("%s statement %s is rejected in the read-only mode", s.Class, s.Head)
+}
+
+// statsCounts returns the counts of SHOW STATS in the space by "Type/Name", i.e. "Tag/player"
+// and "Space/vertices", which are collected by the last stats job
+func statsCounts(s *nebulago.Session, space string) (map[string]int64, error) {
+	if res, err := s.Execute(fmt.Sprintf("USE `%s`", space)); err != nil {
+		return nil, err
+	} else if !res.IsSucceed() {
+		return nil, fmt.Errorf("%s", res.GetErrorMsg())
+	}
+	res, err := s.Execute("SHOW STATS")
+	if err != nil {
+		return nil, err
+	}
+	if !res.IsSucceed() {
+		return nil, fmt.Errorf("%s", res.GetErrorMsg())
+	}
+	counts := make(map[string]int64)
+	for i := 0; i < res.GetRowSize(); i++ {
+		record, err := res.GetRowValuesByIndex(i)
+		if err != nil {
+			return nil, err
+		}
+		var cells [3]*nebulago.ValueWrapper
+		for j := range cells {
+			if cells[j], err = record.GetValueByIndex(j); err != nil {
+				return nil, err
+			}
+		}
+		kind, _ := cells[0].AsString()
+		name, _ := cells[1].AsString()
+		counts[kind+"/"+name], _ = cells[2].AsInt()
+	}
+	return counts, nil
+}
+
+// affected describes what the destructive statement affects, the counts are from SHOW STATS
+func affected(d ngql.Danger, space string) string {
+	// DELETE doesn't have a name
+	if d.Name == "" {
+		return "Everything returned by the statement before it will be deleted, and no WHERE clause filters them"
+	}
+	switch d.Kind {
+	case "HOSTS":
+		return fmt.Sprintf("The partitions on %s will be moved to the other hosts", d.Name)
+	case waiter.KindTagIndex, waiter.KindEdgeIndex, "FULLTEXT INDEX":
+		return fmt.Sprintf("%s %s of space %s will be dropped", strings.ToLower(d.Kind), d.Name, space)
+	}
+
+	if d.Kind == waiter.KindSpace {
+		space = d.Name
+	}
+	s, err := newSession()
+	if err != nil {
+		return "The statistics are not available, " + err.Error()
+	}
+	defer s.Release()
+	counts, err := statsCounts(s, space)
+	if err != nil {
+		return "The statistics are not available, " + err.Error()
+	}
+	const hint = "(counted by the last stats job, run `SUBMIT JOB STATS' to update them)"
+	switch d.Kind {
+	case waiter.KindSpace:
+		return fmt.Sprintf("Space %s has %d vertices and %d edges %s", d.Name, counts["Space/vertices"], counts["Space/edges"], hint)
+	case waiter.KindTag:
+		return fmt.Sprintf("Tag %s of space %s is on %d vertices %s", d.Name, space, counts["Tag/"+d.Name], hint)
+	default:
+		return fmt.Sprintf("Edge type %s of space %s has %d edges %s", d.Name, space, counts["Edge/"+d.Name], hint)
+	}
+}
+
+// confirmDestructive asks for the confirmation of the destructive statements by typing
+// the names of the objects, it returns whether to execute the line
+func confirmDestructive(line, space string) bool {
+	for _, d := range ngql.Destructive(line) {
+		fmt.Printf("Warning: %s is destructive. %s.\n", d.Head, affected(d, space))
+		confirmation := d.Confirmation()
+		p := promptui.Prompt{
+			Label: fmt.Sprintf("Type %s to confirm", confirmation),
+		}
+		input, err := p.Run()
+		if err != nil || strings.TrimSpace(input) != confirmation {
+			return false
+		}
+	}
+	return true
+}
+
+// killQueries kills the running queries of the session by a side session, and returns the number of them
+func killQueries(c cli.Cli, sessionID int64) (int, error) {
+	s, err := newSession()
+	if err != nil {
+		return 0, err
+	}
+	defer s.Release()
+	queries, err := top.SessionQueries(s, sessionID)
+	if err != nil {
+		return 0, err
+	}
+	for i, q := range queries {
+		if err := top.Kill(auditedSession{c: c, session: s}, q); err != nil {
+			return i, err
+		}
+		printConsoleResp(fmt.Sprintf("Killed the query of session %d plan %d on %s, which ran for %v, the status was %s",
+			q.SessionID, q.PlanID, q.Host, q.Duration, q.Status))
+	}
+	return len(queries), nil
+}
+
+var (
+	// errCancelled is returned by executeCancellable if the query is killed by Ctrl-C
+	errCancelled = errors.New("cancelled by Ctrl-C")
+	// errNotKilled is returned by executeCancellable if it gives up killing the query
+	errNotKilled = errors.New("the query could not be killed")
+)
+
+// The query may not be registered yet when it's killed, so the kill is retried with the backoff
+// doubled from killBackoff, and it's given up after killAttempts
+const (
+	killAttempts = 8
+	killBackoff  = 100 * time.Millisecond
+)
+
+// executeCancellable executes the statement by the session. Ctrl-C or the statement timeout kills
+// the query on the server instead of quitting the console, and the result of the killed query is
+// returned with the reason. The result is waited for, so the session is usable afterwards, unless
+// the query could not be killed, then errNotKilled is returned without the result.
+func executeCancellable(c cli.Cli, stmt string) (res *nebulago.ResultSet, stopped error, err error) {
+	type result struct {
+		res *nebulago.ResultSet
+		err error
+	}
+	done := make(chan result, 1)
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt)
+	defer signal.Stop(interrupt)
+	var expired <-chan time.Time
+	if g_statementTimeout > 0 {
+		timer := time.NewTimer(g_statementTimeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+
+	go func() {
+		res, err := session.ExecuteWithParameter(stmt, parameterMap)
+		done <- result{res, err}
+	}()
+	var retry <-chan time.Time
+	attempts, backoff := 0, killBackoff
+	for {
+		select {
+		case r := <-done:
+			return r.res, stopped, r.err
+		case <-interrupt:
+			fmt.Println()
+			stopped = errCancelled
+		case <-expired:
+			stopped = fmt.Errorf("statement timed out after %v", g_statementTimeout)
+		case <-retry:
+		}
+		killed, err := killQueries(c, session.GetSessionID())
+		if err == nil && killed > 0 {
+			// The killed query returns soon
+			retry, attempts, backoff = nil, 0, killBackoff
+			continue
+		}
+		if err == nil {
+			err = errors.New("no running query found")
+		}
+		if attempts++; attempts >= killAttempts {
+			return nil, stopped, fmt.Errorf("%w after %d attempts, %s, the session is busy until it finishes", errNotKilled, attempts, err.Error())
+		}
+		retry = time.After(backoff)
+		backoff *= 2
+	}
+}
+
+// setStatementTimeout shows or sets the statement timeout, :timeout [duration|off]
+func setStatementTimeout(args []string) {
+	if len(args) == 0 {
+		if g_statementTimeout == 0 {
+			printConsoleResp("Statement timeout is off")
+		} else {
+			printConsoleResp(fmt.Sprintf("Statement timeout is %v", g_statementTimeout))
+		}
+		return
+	}
+	if strings.EqualFold(args[0], "off") {
+		g_statementTimeout = 0
+		return
+	}
+	d, err := time.ParseDuration(args[0])
+	if err != nil || d < 0 {
+		printConsoleResp("Error: invalid timeout " + args[0] + ", usage: :timeout [duration|off], e.g. :timeout 30s")
+		return
+	}
+	g_statementTimeout = d
+}
+
 // Loop the request util fatal or timeout
 // We treat one line as one query
 // Add line break yourself as `SHOW \<CR>HOSTS`
 func loop(c cli.Cli) error {
 	for {
-		line, exit, err := c.ReadLine()
-		if err != nil {
-			return err
-		}
-		if exit { // Ctrl+D
-			fmt.Println()
-			return nil
+		var line string
+		// The statement rerun from the history is executed instead of reading the input
+		if g_pendingLine != "" && c.Interactive() {
+			line, g_pendingLine = g_pendingLine, ""
+		} else {
+			input, exit, err := c.ReadLine()
+			if err != nil {
+				return err
+			}
+			if exit { // Ctrl+D
+				fmt.Println()
+				return nil
+			}
+			line = input
 		}
 		if len(line) == 0 { // 1). The line input is empty, or 2). user presses ctrlC so the input is truncated
 			continue
 		}
+		lineStart, lineSpace := time.Now(), c.GetSpace()
+		if c.IsPlayingData() && g_playProgress != nil {
+			statements, _ := c.Position()
+			g_playProgress.update(statements-1, false)
+		}
 		// Console side command
 		if isLocal, cmd, args := isConsoleCmd(line); isLocal {
 			if cmd == Quit {
 				return nil
 			}
+			start := time.Now()
 			executeConsoleCmd(c, cmd, args)
+			auditLog(c, audit.Entry{Kind: audit.KindConsole, Statement: line, LatencyUs: time.Since(start).Microseconds()})
+			if cmd != History {
+				recordHistory(c, line, lineSpace, lineStart)
+			}
+			continue
+		}
+		// Server side command, the credentials are masked in the messages about it
+		secrets, redacted := ngql.Secrets(line), ngql.Redact(line)
+		// i.e. recalled by the arrow keys or Ctrl-X
+		if c.Interactive() && !c.IsPlayingData() && ngql.HasRedacted(line) {
+			auditLog(c, audit.Entry{Kind: audit.KindStatement, Statement: line, Error: errRedacted.Error()})
+			c.SetRespError(fmt.Sprintf("an error occurred when executing: %s, %s", redacted, errRedacted.Error()))
+			printConsoleResp("Error: " + errRedacted.Error())
+			g_repeats = 1
+			continue
+		}
+		if err := checkReadOnly(line); err != nil {
+			auditLog(c, audit.Entry{Kind: audit.KindStatement, Statement: line, Error: err.Error()})
+			recordHistory(c, line, lineSpace, lineStart)
+			c.SetRespError(fmt.Sprintf("an error occurred when executing: %s, %s", redacted, err.Error()))
+			printConsoleResp("Error: " + err.Error())
+			g_repeats = 1
+			if c.IsPlayingData() {
+				return nil
+			}
+			continue
+		}
+		if c.Interactive() && !c.IsPlayingData() && !g_assumeYes && !confirmDestructive(line, c.GetSpace()) {
+			auditLog(c, audit.Entry{Kind: audit.KindStatement, Statement: line, Error: "cancelled by the user"})
+			recordHistory(c, line, lineSpace, lineStart)
+			printConsoleResp("Cancelled")
+			g_repeats = 1
 			continue
 		}
-		// Server side command
 		var t1 int64 = 0
 		var t2 int64 = 0
+		// Merge the profiling data of all runs into one plan when repeating. The aggregation left
+		// by a failed statement, which returns before stopping it, is reset first.
+		planDescPrinter.StopAggregation()
+		if g_repeats > 1 {
+			planDescPrinter.StartAggregation()
+		}
+		if c.IsPlayingData() {
+			g_pendingObjects = append(g_pendingObjects, waiter.Parse(line, c.GetSpace())...)
+		}
 		for i := 0; i < g_repeats; i++ {
 			start := time.Now()
-			res, err := session.ExecuteWithParameter(line, parameterMap)
+			res, stopped, err := executeCancellable(c, line)
+			auditLog(c, resultEntry(line, res, err))
+			if errors.Is(err, errNotKilled) {
+				c.SetRespError(fmt.Sprintf("an error occurred when executing: %s, %s", redacted, err.Error()))
+				printConsoleResp("Error: " + err.Error())
+				if c.IsPlayingData() {
+					return nil
+				}
+				break
+			}
 			if err != nil {
 				return err
 			}
+			// Ctrl-C stops the script, and the interactive console returns to the prompt
+			if stopped == errCancelled && !c.Interactive() {
+				return fmt.Errorf("interrupted when executing: %s", redacted)
+			}
+			if stopped != nil && stopped != errCancelled {
+				c.SetRespError(fmt.Sprintf("an error occurred when executing: %s, %s", redacted, stopped.Error()))
+				printConsoleResp("Error: " + stopped.Error())
+				if c.IsPlayingData() {
+					return nil
+				}
+			}
 			if !res.IsSucceed() && !res.IsPartialSucceed() {
-				c.SetRespError(fmt.Sprintf("an error occurred when executing: %s, [ERROR (%d)]: %s", line, res.GetErrorCode(), res.GetErrorMsg()))
+				c.SetRespError(fmt.Sprintf("an error occurred when executing: %s, [ERROR (%d)]: %s",
+					redacted, res.GetErrorCode(), ngql.RedactMessage(res.GetErrorMsg(), secrets)))
 				if c.IsPlayingData() {
 					return nil
 				}
 			}
 			t1 += res.GetLatency()
+			planDescPrinter.AddToAggregation(res)
 			if c.Output() {
-				duration := printResultSet(res, start)
+				duration := printResultSet(res, start, secrets)
 				t2 += int64(duration / 1000)
 				fmt.Println(time.Now().In(time.Local).Format(time.RFC1123))
 				fmt.Println()
 			}
 			c.SetSpace(res.GetSpaceName())
+			if g_prompt != nil {
+				g_prompt.Latency = time.Duration(res.GetLatency()) * time.Microsecond
+			}
+		}
+		if planDescPrinter.IsAggregating() {
+			if c.Output() {
+				if err := planDescPrinter.PrintAggregation(); err != nil {
+					printConsoleResp("Error: " + err.Error())
+				}
+			}
+			planDescPrinter.StopAggregation()
 		}
 		if g_repeats > 1 {
 			fmt.Printf("Executed %v times, (total time spent %d/%d us), (average time spent %d/%d us)\n", g_repeats, t1, t2, t1/int64(g_repeats), t2/int64(g_repeats))
 			fmt.Println()
 		}
 		g_repeats = 1
+		recordHistory(c, line, lineSpace, lineStart)
 	}
 }
 
@@ -454,6 +1774,17 @@ var (
 	enableHttp2           *bool   = flag.Bool("enable_http2", false, "whether to enable http2")
 
 	customSSL = true
+
+	auditLogPath     = flag.String("audit-log", "", "Append the executed statements and console commands to the file as JSON Lines")
+	auditLogMaxSize  = flag.Int("audit-log-max-size", 100, "The size in MB the audit log is rotated at, 0 means never rotating")
+	auditLogParams   = flag.Bool("audit-log-params", false, "Include the values of the parameters in the audit log, they are redacted by default")
+	assumeYes        = flag.Bool("yes", false, "Execute the destructive statements without confirmation, i.e. DROP SPACE")
+	readOnly         = flag.Bool("read-only", false, "Reject the statements which don't only read, including those of :play, :source and -f")
+	profile          = flag.String("profile", "", "The name of the connection profile, which keys the history, <user>@<address>:<port> by default")
+	historySize      = flag.Int("history-size", history.DefaultMaxSize, "The number of the statements kept in the history of a connection profile")
+	statementTimeout = flag.Duration("statement-timeout", 0, "The timeout of every statement, e.g. 30s, the query is killed when it expires, 0 means never timeout")
+	promptTemplate   = flag.String("prompt", "", "The template of the prompt, e.g. \"{red}PROD{reset} {user}@{host} [{space}]> \", $NEBULA_CONSOLE_PROMPT by default")
+	highlight        = flag.String("highlight", "default", "The syntax highlighting of the input with -enable_go_prompt, e.g. keyword=red+bold,comment=darkgray, or off")
 )
 
 func init() {
@@ -466,6 +1797,74 @@ func init() {
 	flag.StringVar(file, "file", "", "The nGQL script file name")
 }
 
+// newPrompt parses the template of -prompt or $NEBULA_CONSOLE_PROMPT, the version of graphd
+// is queried only if it's shown
+func newPrompt() (*cli.Prompt, error) {
+	template := *promptTemplate
+	if template == "" {
+		template = os.Getenv("NEBULA_CONSOLE_PROMPT")
+	}
+	if template == "" {
+		template = cli.DefaultPrompt
+	}
+	p, err := cli.NewPrompt(template)
+	if err != nil {
+		return nil, err
+	}
+	p.Host = fmt.Sprintf("%s:%d", *address, *port)
+	p.Profile = connectionProfile()
+	p.ReadOnly = *readOnly
+	p.Color = !cli.NoColor()
+	if p.Uses("version") {
+		p.Version = graphdVersion(p.Host)
+	}
+	return p, nil
+}
+
+// graphdVersion returns the version of the graphd connected, or of the first one if it's not found
+// by the address, i.e. a domain name
+func graphdVersion(host string) string {
+	res, err := session.Execute("SHOW HOSTS GRAPH")
+	if err != nil || !res.IsSucceed() || res.GetRowSize() == 0 {
+		return ""
+	}
+	column := func(row int, name string) string {
+		record, err := res.GetRowValuesByIndex(row)
+		if err != nil {
+			return ""
+		}
+		val, err := record.GetValueByColName(name)
+		if err != nil {
+			return ""
+		}
+		if s, err := val.AsString(); err == nil {
+			return s
+		}
+		return val.String()
+	}
+	for i := 0; i < res.GetRowSize(); i++ {
+		if fmt.Sprintf("%s:%s", column(i, "Host"), column(i, "Port")) == host {
+			return column(i, "Version")
+		}
+	}
+	return column(0, "Version")
+}
+
+// connectionProfile returns the name of the connection profile
+func connectionProfile() string {
+	if *profile != "" {
+		return *profile
+	}
+	return fmt.Sprintf("%s@%s:%d", *username, *address, *port)
+}
+
+var unsafeFileNameRegexp = regexp.MustCompile(`[^A-Za-z0-9._@-]`)
+
+// profileFileName converts the profile name to a file name, i.e. root@127.0.0.1_9669
+func profileFileName(profile string) string {
+	return unsafeFileNameRegexp.ReplaceAllString(profile, "_")
+}
+
 func validateFlags() error {
 	var missingFields []string
 
@@ -506,6 +1905,7 @@ var session *nebulago.Session
 func main() {
 	flag.Parse()
 	parameterMap = make(ParameterMap)
+	g_statementTimeout = *statementTimeout
 
 	if flag.NFlag() == 1 && *version {
 		fmt.Printf("nebula-console version Git: %s, Build Time: %s\n", gitCommit, buildDate)
@@ -554,12 +1954,19 @@ func main() {
 		historyHome = filepath.Dir(ex) // Set to executable folder
 	}
 
+	profilesFile := path.Join(historyHome, ".nebula_console", "profiles.json")
+	if settings, err := profiles.Load(profilesFile, connectionProfile()); err != nil {
+		log.Fatalf("Failed to load the settings of profile %s, %s", connectionProfile(), err.Error())
+	} else {
+		g_assumeYes = *assumeYes || settings.Yes
+	}
+
 	hostAddress := nebulago.HostAddress{Host: *address, Port: *port}
 	hostList := []nebulago.HostAddress{hostAddress}
 	poolConfig := nebulago.PoolConfig{
 		TimeOut:         time.Duration(*timeout) * time.Millisecond,
 		IdleTime:        0 * time.Millisecond,
-		MaxConnPoolSize: 2,
+		MaxConnPoolSize: 16,
 		MinConnPoolSize: 1,
 		UseHTTP2:        *enableHttp2,
 	}
@@ -596,14 +2003,55 @@ func main() {
 	}
 	defer session.Release()
 
+	if *auditLogPath != "" {
+		g_auditLogger, err = audit.NewLogger(*auditLogPath)
+		if err != nil {
+			log.Fatalf("Failed to open audit log %s, %s", *auditLogPath, err.Error())
+		}
+		g_auditLogger.MaxSize = int64(*auditLogMaxSize) << 20
+		g_auditLogger.IncludeParams = *auditLogParams
+		defer g_auditLogger.Close()
+	}
+
+	if flag.Arg(0) == "migrate" {
+		if err := migrate(flag.Args()[1:]); err != nil {
+			log.Fatalf("Migrate failed, %s", err.Error())
+		}
+		return
+	}
+
 	welcome(interactive)
 	defer bye(*username, interactive)
 
 	var c cli.Cli = nil
 	// Loop the request
 	if interactive {
-		historyFile := path.Join(historyHome, ".nebula_history")
-		c = cli.NewiCli(historyFile, *username, *goPrompt)
+		historyFile := path.Join(historyHome, ".nebula_console", "history", profileFileName(connectionProfile())+".jsonl")
+		_, statErr := os.Stat(historyFile)
+		g_history, err = history.Open(historyFile, *historySize)
+		if err != nil {
+			log.Fatalf("Open history file %s failed, %s", historyFile, err.Error())
+		}
+		// The plain history file of the older versions is imported by the new profiles
+		if os.IsNotExist(statErr) {
+			if f, err := os.Open(path.Join(historyHome, ".nebula_history")); err == nil {
+				g_history.Import(f)
+				f.Close()
+			}
+		}
+		defer g_history.Close()
+		theme, err := cli.ParseTheme(*highlight)
+		if err != nil {
+			log.Fatalf("Invalid -highlight %s, %s", *highlight, err.Error())
+		}
+		if cli.NoColor() {
+			theme = nil
+		}
+		g_prompt, err = newPrompt()
+		if err != nil {
+			log.Fatalf("Invalid prompt, %s", err.Error())
+		}
+		c = cli.NewiCli(g_history.Statements(), *username, *goPrompt, theme, g_prompt)
 	} else if *script != "" {
 		c = cli.NewnCli(strings.NewReader(*script), true, *username, nil)
 	} else if *file != "" {
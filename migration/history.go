@@ -0,0 +1,209 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package migration
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/vesoft-inc/nebula-console/importer"
+	"github.com/vesoft-inc/nebula-console/printer"
+	"github.com/vesoft-inc/nebula-console/waiter"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+)
+
+// HistoryTag is the bookkeeping tag in the target space, one vertex per applied migration
+const HistoryTag = "__migration_history"
+
+// Record is a migration applied to the space
+type Record struct {
+	Version       string
+	Description   string
+	Script        string
+	Checksum      string
+	InstalledOn   string
+	ExecutionTime int64
+	Success       bool
+}
+
+// history reads and writes the records over the session
+type history struct {
+	session *nebula.Session
+	space   string
+	vidType string
+	// audit is called with the result of every statement writing the history
+	audit func(stmt string, res *nebula.ResultSet, err error)
+}
+
+func execute(session *nebula.Session, stmt string) (*nebula.ResultSet, error) {
+	res, err := session.Execute(stmt)
+	if err != nil {
+		return nil, err
+	}
+	if !res.IsSucceed() {
+		return nil, fmt.Errorf("an error occurred when executing: %s, [ERROR (%d)]: %s", stmt, res.GetErrorCode(), res.GetErrorMsg())
+	}
+	return res, nil
+}
+
+// write executes the statement changing the history, and passes the result to the audit hook
+func (h *history) write(stmt string) error {
+	res, err := h.session.Execute(stmt)
+	if h.audit != nil {
+		h.audit(stmt, res, err)
+	}
+	if err == nil && !res.IsSucceed() {
+		err = fmt.Errorf("an error occurred when executing: %s, [ERROR (%d)]: %s", stmt, res.GetErrorCode(), res.GetErrorMsg())
+	}
+	return err
+}
+
+// spaceExists checks whether the space is created, it may be created by the first migration
+func spaceExists(session *nebula.Session, space string) bool {
+	_, err := execute(session, fmt.Sprintf("DESCRIBE SPACE `%s`", space))
+	return err == nil
+}
+
+// use switches the session to the space, and returns false if the space does not exist
+func (h *history) use() (bool, error) {
+	if !spaceExists(h.session, h.space) {
+		return false, nil
+	}
+	if _, err := execute(h.session, fmt.Sprintf("USE `%s`", h.space)); err != nil {
+		return false, err
+	}
+	if h.vidType == "" {
+		vidType, err := importer.DescribeVidType(h.session, h.space)
+		if err != nil {
+			return false, err
+		}
+		h.vidType = vidType
+	}
+	return true, nil
+}
+
+var fixedStringRegexp = regexp.MustCompile(`(?i)^FIXED_STRING\((\d+)\)$`)
+
+// normalize returns the numeric parts of the version joined by dots, i.e. 1.2 of V001_2,
+// so that V1 and V001 are the same version as Scan treats them
+func normalize(version string) string {
+	var parts []string
+	for _, part := range versionRegexp.Split(strings.TrimPrefix(version, "V"), -1) {
+		if n, err := strconv.Atoi(part); err == nil {
+			part = strconv.Itoa(n)
+		}
+		parts = append(parts, part)
+	}
+	return strings.Join(parts, ".")
+}
+
+// vid is the vertex of the version, which is hashed in the spaces of INT64 vid,
+// and is shortened to a digest if it's longer than the FIXED_STRING vid
+func (h *history) vid(version string) string {
+	key := "__migration_" + normalize(version)
+	if strings.HasPrefix(strings.ToUpper(h.vidType), "INT") {
+		return fmt.Sprintf("hash(%s)", printer.QuoteNGQLString(key))
+	}
+	if m := fixedStringRegexp.FindStringSubmatch(strings.TrimSpace(h.vidType)); m != nil {
+		if n, _ := strconv.Atoi(m[1]); n < len(key) {
+			sum := sha256.Sum256([]byte(key))
+			key = hex.EncodeToString(sum[:])
+			if n < len(key) {
+				key = key[:n]
+			}
+		}
+	}
+	return printer.QuoteNGQLString(key)
+}
+
+func recordString(record *nebula.Record, col string) string {
+	val, err := record.GetValueByColName(col)
+	if err != nil || val.IsNull() || val.IsEmpty() {
+		return ""
+	}
+	if s, err := val.AsString(); err == nil {
+		return s
+	}
+	return val.String()
+}
+
+// load fetches the records of the versions
+func (h *history) load(versions []string) (map[string]*Record, error) {
+	records := make(map[string]*Record)
+	ok, err := h.use()
+	if err != nil || !ok || len(versions) == 0 {
+		return records, err
+	}
+	if _, err := execute(h.session, fmt.Sprintf("DESCRIBE TAG `%s`", HistoryTag)); err != nil {
+		// nothing has been applied by the migration runner yet
+		return records, nil
+	}
+	vids := make([]string, 0, len(versions))
+	// the records are keyed by the versions asked for, which may be written differently, i.e. V001 of V1
+	asked := make(map[string]string, len(versions))
+	for _, v := range versions {
+		vids = append(vids, h.vid(v))
+		asked[normalize(v)] = v
+	}
+	res, err := execute(h.session, fmt.Sprintf("FETCH PROP ON `%[1]s` %[2]s YIELD `%[1]s`.version AS version, "+
+		"`%[1]s`.description AS description, `%[1]s`.script AS script, `%[1]s`.checksum AS checksum, "+
+		"`%[1]s`.installed_on AS installed_on, `%[1]s`.execution_time AS execution_time, `%[1]s`.success AS success",
+		HistoryTag, strings.Join(vids, ", ")))
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < res.GetRowSize(); i++ {
+		row, err := res.GetRowValuesByIndex(i)
+		if err != nil {
+			return nil, err
+		}
+		r := &Record{
+			Version:     recordString(row, "version"),
+			Description: recordString(row, "description"),
+			Script:      recordString(row, "script"),
+			Checksum:    recordString(row, "checksum"),
+			InstalledOn: recordString(row, "installed_on"),
+		}
+		if val, err := row.GetValueByColName("execution_time"); err == nil {
+			r.ExecutionTime, _ = val.AsInt()
+		}
+		if val, err := row.GetValueByColName("success"); err == nil {
+			r.Success, _ = val.AsBool()
+		}
+		if v, ok := asked[normalize(r.Version)]; ok && r.Version != "" {
+			records[v] = r
+		}
+	}
+	return records, nil
+}
+
+// save writes the record, the bookkeeping tag is created at the first time, and it's
+// waited for until it's propagated by heartbeat
+func (h *history) save(r *Record) error {
+	ok, err := h.use()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("space %s does not exist after migration %s, it should be created by the first migration", h.space, r.Version)
+	}
+	if err := h.write(fmt.Sprintf("CREATE TAG IF NOT EXISTS `%s`(version string, description string, "+
+		"script string, checksum string, installed_on string, execution_time int64, success bool)", HistoryTag)); err != nil {
+		return err
+	}
+	tag := waiter.Object{Kind: waiter.KindTag, Space: h.space, Name: HistoryTag}
+	if err := waiter.Wait(h.session, []waiter.Object{tag}, waiter.DefaultTimeout); err != nil {
+		return err
+	}
+	return h.write(fmt.Sprintf("INSERT VERTEX `%s`(version, description, script, checksum, installed_on, execution_time, success) "+
+		"VALUES %s:(%s, %s, %s, %s, %s, %d, %t)", HistoryTag, h.vid(r.Version),
+		printer.QuoteNGQLString(r.Version), printer.QuoteNGQLString(r.Description), printer.QuoteNGQLString(r.Script),
+		printer.QuoteNGQLString(r.Checksum), printer.QuoteNGQLString(r.InstalledOn), r.ExecutionTime, r.Success))
+}
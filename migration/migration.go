@@ -0,0 +1,91 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package migration
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Migration is a versioned script named like V001__init.ngql or V1_2__add_index.ngql
+type Migration struct {
+	Version     string
+	Description string
+	File        string
+	Checksum    string
+	// the numeric parts of the version, i.e. [1, 2] of V1_2
+	parts []int
+}
+
+var (
+	fileRegexp    = regexp.MustCompile(`^V(\d+(?:[._]\d+)*)__(.+)\.ngql$`)
+	versionRegexp = regexp.MustCompile(`[._]`)
+)
+
+func less(a, b []int) bool {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			return a[i] < b[i]
+		}
+	}
+	return len(a) < len(b)
+}
+
+func checksum(filename string) (string, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Scan lists the migrations in the directory ordered by the versions,
+// the files not named as migrations are ignored
+func Scan(dir string) ([]*Migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var migrations []*Migration
+	versions := make(map[string]string)
+	for _, entry := range entries {
+		m := fileRegexp.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || m == nil {
+			continue
+		}
+		migration := &Migration{
+			Version:     "V" + m[1],
+			Description: strings.ReplaceAll(m[2], "_", " "),
+			File:        filepath.Join(dir, entry.Name()),
+		}
+		for _, part := range versionRegexp.Split(m[1], -1) {
+			n, _ := strconv.Atoi(part)
+			migration.parts = append(migration.parts, n)
+		}
+		// V1 and V001 are the same version
+		key := fmt.Sprint(migration.parts)
+		if other, ok := versions[key]; ok {
+			return nil, fmt.Errorf("duplicated version of %s and %s", other, entry.Name())
+		}
+		versions[key] = entry.Name()
+		if migration.Checksum, err = checksum(migration.File); err != nil {
+			return nil, err
+		}
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return less(migrations[i].parts, migrations[j].parts)
+	})
+	return migrations, nil
+}
@@ -0,0 +1,139 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package migration
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func writeFiles(t *testing.T, names ...string) string {
+	dir := t.TempDir()
+	for _, name := range names {
+		if strings.HasSuffix(name, "/") {
+			if err := os.Mkdir(filepath.Join(dir, name), 0700); err != nil {
+				t.Fatal(err)
+			}
+			continue
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(name), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func TestScan(t *testing.T) {
+	cases := []struct {
+		files    []string
+		versions []string
+		wantErr  bool
+	}{
+		{nil, nil, false},
+		// The versions are ordered by the numbers rather than the names
+		{[]string{"V10__c.ngql", "V2__b.ngql", "V001__a.ngql"}, []string{"V001", "V2", "V10"}, false},
+		{[]string{"V1_10__c.ngql", "V1_2__b.ngql", "V1__a.ngql", "V1.2.1__d.ngql"}, []string{"V1", "V1_2", "V1.2.1", "V1_10"}, false},
+		// The other files are ignored
+		{[]string{"V1__a.ngql", "README.md", "V2_b.ngql", "v3__c.ngql", "V4__d.ngql/"}, []string{"V1"}, false},
+		{[]string{"V1__a.ngql", "V001__b.ngql"}, nil, true},
+	}
+	for _, c := range cases {
+		migrations, err := Scan(writeFiles(t, c.files...))
+		if (err != nil) != c.wantErr {
+			t.Errorf("Scan(%v) error = %v, want error %t", c.files, err, c.wantErr)
+			continue
+		}
+		var versions []string
+		for _, m := range migrations {
+			versions = append(versions, m.Version)
+		}
+		if !reflect.DeepEqual(versions, c.versions) {
+			t.Errorf("Scan(%v) = %v, want %v", c.files, versions, c.versions)
+		}
+	}
+
+	migrations, err := Scan(writeFiles(t, "V1_2__add_index.ngql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := migrations[0]
+	if m.Description != "add index" || filepath.Base(m.File) != "V1_2__add_index.ngql" || len(m.Checksum) != 64 {
+		t.Errorf("Scan = %+v", m)
+	}
+
+	if _, err := Scan(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Errorf("Scan of a missing directory succeeded, want an error")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	v1 := &Migration{Version: "V1", File: "V1__a.ngql", Checksum: "1"}
+	v2 := &Migration{Version: "V2", File: "V2__b.ngql", Checksum: "2"}
+	v3 := &Migration{Version: "V3", File: "V3__c.ngql", Checksum: "3"}
+	applied := func(checksum string) *Record {
+		return &Record{Checksum: checksum, Success: true}
+	}
+	cases := []struct {
+		name    string
+		records map[string]*Record
+		pending []*Migration
+		wantErr string
+	}{
+		{"none applied", nil, []*Migration{v1, v2, v3}, ""},
+		{"some applied", map[string]*Record{"V1": applied("1")}, []*Migration{v2, v3}, ""},
+		{"all applied", map[string]*Record{"V1": applied("1"), "V2": applied("2"), "V3": applied("3")}, nil, ""},
+		{"failed retried", map[string]*Record{"V1": applied("1"), "V2": {Checksum: "old"}}, []*Migration{v2, v3}, ""},
+		{"changed", map[string]*Record{"V1": applied("changed")}, nil, "checksum of V1__a.ngql has changed"},
+		{"out of order", map[string]*Record{"V1": applied("1"), "V3": applied("3")}, nil, "V2 is pending but the later version V3 has been applied"},
+	}
+	for _, c := range cases {
+		pending, err := validate([]*Migration{v1, v2, v3}, c.records)
+		if c.wantErr != "" {
+			if err == nil || !strings.Contains(err.Error(), c.wantErr) {
+				t.Errorf("%s: validate error = %v, want %q", c.name, err, c.wantErr)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: validate failed, %s", c.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(pending, c.pending) {
+			t.Errorf("%s: validate = %v, want %v", c.name, pending, c.pending)
+		}
+	}
+}
+
+func TestHistoryVid(t *testing.T) {
+	cases := []struct {
+		vidType string
+		version string
+		want    string
+	}{
+		{"INT64", "V1_2", `hash("__migration_1.2")`},
+		{"FIXED_STRING(32)", "V001.02", `"__migration_1.2"`},
+		// The key longer than the vid is shortened to the digest
+		{"FIXED_STRING(8)", "V1", `"` + digest("__migration_1")[:8] + `"`},
+		{"FIXED_STRING(8)", "V001", `"` + digest("__migration_1")[:8] + `"`},
+		{"FIXED_STRING(128)", "V3", `"__migration_3"`},
+	}
+	for _, c := range cases {
+		h := &history{vidType: c.vidType}
+		if got := h.vid(c.version); got != c.want {
+			t.Errorf("vid(%s) in %s = %s, want %s", c.version, c.vidType, got, c.want)
+		}
+	}
+}
+
+func digest(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,165 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package migration
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+)
+
+// Runner applies the pending migrations in the directory to the space
+type Runner struct {
+	Session *nebula.Session
+	Space   string
+	Dir     string
+	// DryRun prints the pending migrations instead of applying them
+	DryRun bool
+	// Execute runs the statements of the file over the session, it stops at the first failed statement
+	Execute func(file string) error
+	// Audit is called with the result of every statement writing the history, i.e. to write the audit log
+	Audit func(stmt string, res *nebula.ResultSet, err error)
+}
+
+func (r *Runner) history() *history {
+	return &history{session: r.Session, space: r.Space, audit: r.Audit}
+}
+
+func (r *Runner) load() ([]*Migration, map[string]*Record, error) {
+	migrations, err := Scan(r.Dir)
+	if err != nil {
+		return nil, nil, err
+	}
+	versions := make([]string, 0, len(migrations))
+	for _, m := range migrations {
+		versions = append(versions, m.Version)
+	}
+	records, err := r.history().load(versions)
+	if err != nil {
+		return nil, nil, err
+	}
+	return migrations, records, nil
+}
+
+func state(m *Migration, record *Record) string {
+	switch {
+	case record == nil:
+		return "Pending"
+	case !record.Success:
+		return "Failed"
+	case record.Checksum != m.Checksum:
+		return "Checksum mismatch"
+	default:
+		return "Success"
+	}
+}
+
+// Status prints the state of the migrations
+func (r *Runner) Status() error {
+	migrations, records, err := r.load()
+	if err != nil {
+		return err
+	}
+	if len(migrations) == 0 {
+		fmt.Printf("No migration found in %s\n", r.Dir)
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "Version\tDescription\tState\tInstalled On\tExecution Time")
+	for _, m := range migrations {
+		record := records[m.Version]
+		installedOn, executionTime := "", ""
+		if record != nil {
+			installedOn = record.InstalledOn
+			executionTime = (time.Duration(record.ExecutionTime) * time.Millisecond).String()
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", m.Version, m.Description, state(m, record), installedOn, executionTime)
+	}
+	return w.Flush()
+}
+
+// validate refuses the migrations changed after applied, and the pending migrations
+// older than an applied one
+func validate(migrations []*Migration, records map[string]*Record) ([]*Migration, error) {
+	var pending []*Migration
+	for _, m := range migrations {
+		record := records[m.Version]
+		if record == nil || !record.Success {
+			pending = append(pending, m)
+			continue
+		}
+		if record.Checksum != m.Checksum {
+			return nil, fmt.Errorf("checksum of %s has changed since it was applied on %s, "+
+				"please add a new migration instead of modifying an applied one", filepath.Base(m.File), record.InstalledOn)
+		}
+		if len(pending) > 0 {
+			return nil, fmt.Errorf("%s is pending but the later version %s has been applied", pending[0].Version, m.Version)
+		}
+	}
+	return pending, nil
+}
+
+// Migrate applies the pending migrations one by one, it stops at the first failed migration
+func (r *Runner) Migrate() error {
+	migrations, records, err := r.load()
+	if err != nil {
+		return err
+	}
+	pending, err := validate(migrations, records)
+	if err != nil {
+		return err
+	}
+	if len(pending) == 0 {
+		fmt.Printf("Space %s is up to date, no migration to apply\n", r.Space)
+		return nil
+	}
+
+	if r.DryRun {
+		for _, m := range pending {
+			content, err := os.ReadFile(m.File)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("Pending migration %s - %s (%s):\n%s\n", m.Version, m.Description, m.File, content)
+		}
+		return nil
+	}
+
+	h := r.history()
+	for _, m := range pending {
+		fmt.Printf("Migrating space %s to %s - %s...\n", r.Space, m.Version, m.Description)
+		// The first migration may create the space
+		if _, err := h.use(); err != nil {
+			return err
+		}
+		start := time.Now()
+		execErr := r.Execute(m.File)
+		record := &Record{
+			Version:       m.Version,
+			Description:   m.Description,
+			Script:        filepath.Base(m.File),
+			Checksum:      m.Checksum,
+			InstalledOn:   start.Format(time.RFC3339),
+			ExecutionTime: time.Since(start).Milliseconds(),
+			Success:       execErr == nil,
+		}
+		if err := h.save(record); err != nil {
+			if execErr != nil {
+				return fmt.Errorf("migration %s failed, %s", m.Version, execErr.Error())
+			}
+			return fmt.Errorf("migration %s is applied but not recorded, %s", m.Version, err.Error())
+		}
+		if execErr != nil {
+			return fmt.Errorf("migration %s failed, %s", m.Version, execErr.Error())
+		}
+	}
+	fmt.Printf("Applied %d migrations, space %s is now at %s\n", len(pending), r.Space, pending[len(pending)-1].Version)
+	return nil
+}
@@ -0,0 +1,188 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package ngql
+
+import (
+	"strings"
+)
+
+// Class is what a statement does, the classes are ordered by how dangerous they are
+type Class int
+
+const (
+	// Read only reads the data and the schema, or switches the space
+	Read Class = iota
+	// Write inserts, updates or deletes the data
+	Write
+	// Schema creates, alters or drops the spaces, tags, edge types and indexes
+	Schema
+	// Admin manages the users, roles, hosts, jobs and configs
+	Admin
+	// Unknown is not recognized, which is not read-only for safety
+	Unknown
+)
+
+func (c Class) String() string {
+	switch c {
+	case Read:
+		return "read"
+	case Write:
+		return "write"
+	case Schema:
+		return "schema"
+	case Admin:
+		return "admin"
+	}
+	return "unknown"
+}
+
+// The statements only reading, by the first keyword
+var readHeads = map[string]bool{
+	"GO": true, "MATCH": true, "OPTIONAL": true, "LOOKUP": true, "FETCH": true, "FIND": true,
+	"GET": true, "SHOW": true, "DESCRIBE": true, "DESC": true, "YIELD": true, "RETURN": true,
+	"UNWIND": true, "WITH": true, "USE": true, "ORDER": true, "LIMIT": true, "GROUP": true,
+	"SAMPLE": true,
+}
+
+var writeHeads = map[string]bool{
+	"INSERT": true, "UPDATE": true, "UPSERT": true, "DELETE": true, "CLEAR": true,
+}
+
+var schemaHeads = map[string]bool{
+	"CREATE": true, "ALTER": true, "DROP": true, "REBUILD": true, "RENAME": true,
+}
+
+// GRANT, REVOKE, CHANGE PASSWORD, BALANCE, SUBMIT JOB, KILL QUERY, ADD HOSTS, ...
+var adminHeads = map[string]bool{
+	"GRANT": true, "REVOKE": true, "CHANGE": true, "BALANCE": true, "SUBMIT": true, "STOP": true,
+	"RECOVER": true, "KILL": true, "ADD": true, "DOWNLOAD": true, "INGEST": true, "SIGN": true,
+	"MERGE": true, "DIVIDE": true, "REMOVE": true,
+}
+
+// The objects of CREATE, ALTER and DROP which are administration rather than schema
+var adminObjects = map[string]bool{
+	"USER": true, "ROLE": true, "SNAPSHOT": true, "HOSTS": true, "ZONE": true, "LISTENER": true,
+}
+
+// Statement is a statement of a text and its class
+type Statement struct {
+	// Head is the first two words in upper case, i.e. DROP SPACE
+	Head  string
+	Class Class
+	// Tokens is the significant tokens from the head to the end of the statement
+	Tokens []Token
+	// Group is the index of the statements separated by semicolons, the statements
+	// connected by pipes or set operations are in the same group
+	Group int
+	// Piped is whether the input of the statement is piped from the previous one
+	Piped bool
+	// Assigns is the variable the result of the statement is assigned to, i.e. $v of `$v = GO ...`
+	Assigns string
+}
+
+func classify(words []string) Class {
+	head := words[0]
+	switch {
+	case readHeads[head]:
+		return Read
+	case writeHeads[head]:
+		// UPDATE CONFIGS changes the cluster rather than the data
+		if head == "UPDATE" && len(words) > 1 && words[1] == "CONFIGS" {
+			return Admin
+		}
+		return Write
+	case schemaHeads[head]:
+		if len(words) > 1 && adminObjects[words[1]] {
+			return Admin
+		}
+		return Schema
+	case adminHeads[head]:
+		return Admin
+	}
+	return Unknown
+}
+
+func upper(t Token) string {
+	if t.Kind == Word {
+		return strings.ToUpper(t.Text)
+	}
+	return ""
+}
+
+// Classify returns the statements in the text, which are separated by semicolons and pipes,
+// and combined by UNION, INTERSECT and MINUS. The statements profiled or explained are
+// classified by themselves.
+func Classify(text string) []Statement {
+	tokens := Significant(Tokenize(text))
+	var statements []Statement
+	start, piped, group, depth := true, false, 0, 0
+	assigns := ""
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		if start {
+			// Skip the parentheses of the set operations, the assignment of a variable,
+			// and the EXPLAIN or PROFILE with the format and the braces
+			switch {
+			case t.Kind == Punct && (t.Text == "(" || t.Text == "{"):
+				continue
+			case t.Kind == Parameter && i+1 < len(tokens) && tokens[i+1].Text == "=":
+				assigns = t.Text
+				i++
+				continue
+			case upper(t) == "EXPLAIN" || upper(t) == "PROFILE":
+				if i+3 < len(tokens) && upper(tokens[i+1]) == "FORMAT" && tokens[i+2].Text == "=" {
+					i += 3
+				}
+				continue
+			}
+			var words []string
+			for j := i; j < len(tokens) && j < i+2; j++ {
+				words = append(words, upper(tokens[j]))
+			}
+			statements = append(statements, Statement{
+				Head:    strings.TrimSpace(strings.Join(words, " ")),
+				Class:   classify(words),
+				Group:   group,
+				Piped:   piped,
+				Assigns: assigns,
+			})
+			start, piped, assigns = false, false, ""
+		}
+		last := &statements[len(statements)-1]
+		switch {
+		case t.Kind == Punct && (t.Text == "(" || t.Text == "[" || t.Text == "{"):
+			depth++
+		case t.Kind == Punct && (t.Text == ")" || t.Text == "]" || t.Text == "}"):
+			depth--
+		case t.Kind == Punct && t.Text == ";":
+			start, depth = true, 0
+			group++
+			continue
+		case t.Kind == Punct && t.Text == "|" && depth <= 0:
+			start, piped = true, true
+			continue
+		case (upper(t) == "UNION" || upper(t) == "INTERSECT" || upper(t) == "MINUS") && (i == 0 || tokens[i-1].Text != "."):
+			if i+1 < len(tokens) && (upper(tokens[i+1]) == "ALL" || upper(tokens[i+1]) == "DISTINCT") {
+				i++
+			}
+			start = true
+			continue
+		}
+		last.Tokens = append(last.Tokens, t)
+	}
+	return statements
+}
+
+// ReadOnly checks whether every statement in the text only reads, the first statement
+// which doesn't is returned otherwise
+func ReadOnly(text string) (bool, Statement) {
+	for _, s := range Classify(text) {
+		if s.Class != Read {
+			return false, s
+		}
+	}
+	return true, Statement{}
+}
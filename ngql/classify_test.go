@@ -0,0 +1,91 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package ngql
+
+import (
+	"testing"
+)
+
+func TestClassify(t *testing.T) {
+	type st struct {
+		Head    string
+		Class   Class
+		Group   int
+		Piped   bool
+		Assigns string
+	}
+	cases := []struct {
+		input string
+		want  []st
+	}{
+		{"", nil},
+		{"SHOW SPACES", []st{{"SHOW SPACES", Read, 0, false, ""}}},
+		{"use nba", []st{{"USE NBA", Read, 0, false, ""}}},
+		{"INSERT VERTEX t() VALUES 1:()", []st{{"INSERT VERTEX", Write, 0, false, ""}}},
+		{"UPDATE CONFIGS storage:k=1", []st{{"UPDATE CONFIGS", Admin, 0, false, ""}}},
+		{"CREATE TAG t()", []st{{"CREATE TAG", Schema, 0, false, ""}}},
+		{"CREATE USER bob", []st{{"CREATE USER", Admin, 0, false, ""}}},
+		{"KILL QUERY (plan=1)", []st{{"KILL QUERY", Admin, 0, false, ""}}},
+		{"FOO BAR", []st{{"FOO BAR", Unknown, 0, false, ""}}},
+		{"GO FROM 1 OVER e | DELETE VERTEX $-.id", []st{
+			{"GO FROM", Read, 0, false, ""},
+			{"DELETE VERTEX", Write, 0, true, ""},
+		}},
+		{"USE s; DROP TAG t", []st{
+			{"USE S", Read, 0, false, ""},
+			{"DROP TAG", Schema, 1, false, ""},
+		}},
+		{"$v = GO FROM 1 OVER e; DELETE VERTEX $v.id", []st{
+			{"GO FROM", Read, 0, false, "$v"},
+			{"DELETE VERTEX", Write, 1, false, ""},
+		}},
+		{"PROFILE FORMAT=\"row\" {INSERT EDGE e() VALUES 1->2:()}", []st{{"INSERT EDGE", Write, 0, false, ""}}},
+		{"EXPLAIN MATCH (v) RETURN v", []st{{"MATCH", Read, 0, false, ""}}},
+		{"(GO FROM 1 OVER e) UNION ALL (GO FROM 2 OVER e)", []st{
+			{"GO FROM", Read, 0, false, ""},
+			{"GO FROM", Read, 0, false, ""},
+		}},
+		// The pipes of the expressions are not separators
+		{"YIELD [x IN [1] | x]", []st{{"YIELD", Read, 0, false, ""}}},
+		// The keywords in the strings and comments are ignored
+		{"SHOW HOSTS /* ; DROP SPACE s */ # ; DROP", []st{{"SHOW HOSTS", Read, 0, false, ""}}},
+	}
+	for _, c := range cases {
+		var got []st
+		for _, s := range Classify(c.input) {
+			got = append(got, st{s.Head, s.Class, s.Group, s.Piped, s.Assigns})
+		}
+		if len(got) != len(c.want) {
+			t.Errorf("Classify(%q) = %v, want %v", c.input, got, c.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != c.want[i] {
+				t.Errorf("Classify(%q)[%d] = %v, want %v", c.input, i, got[i], c.want[i])
+			}
+		}
+	}
+}
+
+func TestReadOnly(t *testing.T) {
+	cases := []struct {
+		input string
+		ok    bool
+		head  string
+	}{
+		{"SHOW SPACES; USE nba; MATCH (v) RETURN v LIMIT 1", true, ""},
+		{"GO FROM 1 OVER e | DELETE VERTEX $-.id", false, "DELETE VERTEX"},
+		{"EXPLAIN DROP SPACE s", false, "DROP SPACE"},
+		{"SUBMIT JOB STATS", false, "SUBMIT JOB"},
+		{"FOO", false, "FOO"},
+	}
+	for _, c := range cases {
+		ok, s := ReadOnly(c.input)
+		if ok != c.ok || s.Head != c.head {
+			t.Errorf("ReadOnly(%q) = %t, %q, want %t, %q", c.input, ok, s.Head, c.ok, c.head)
+		}
+	}
+}
@@ -0,0 +1,152 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package ngql
+
+import (
+	"strings"
+)
+
+// Danger is a destructive statement, which drops or clears a space, a tag, an edge type or
+// an index, deletes the unfiltered result of a query, or removes hosts by balancing
+type Danger struct {
+	// Head is the statement without the names, i.e. DROP TAG or BALANCE DATA REMOVE
+	Head string
+	// Kind is SPACE, TAG, EDGE, TAG INDEX, EDGE INDEX, FULLTEXT INDEX, VERTEX, EDGE or HOSTS
+	Kind string
+	// Name is the object dropped or cleared, or the hosts removed
+	Name string
+}
+
+// Confirmation is what should be typed to confirm the statement, the name of the object,
+// or the first word of the statement if there isn't a single object
+func (d Danger) Confirmation() string {
+	if d.Name != "" && d.Kind != "HOSTS" {
+		return d.Name
+	}
+	return strings.Fields(d.Head)[0]
+}
+
+func name(t Token) string {
+	if t.Kind == QuotedName {
+		return strings.Trim(t.Text, "`")
+	}
+	return t.Text
+}
+
+// skipIfExists returns the position after IF EXISTS, if any
+func skipIfExists(tokens []Token, i int) int {
+	if i+1 < len(tokens) && upper(tokens[i]) == "IF" && upper(tokens[i+1]) == "EXISTS" {
+		return i + 2
+	}
+	return i
+}
+
+// dropped returns the kind and the name of the object dropped by DROP or CLEAR
+func dropped(tokens []Token) (string, string, bool) {
+	if len(tokens) < 2 {
+		return "", "", false
+	}
+	kind, i := upper(tokens[1]), 2
+	switch kind {
+	case "SPACE":
+	case "TAG", "EDGE", "FULLTEXT":
+		if i < len(tokens) && upper(tokens[i]) == "INDEX" {
+			kind, i = kind+" INDEX", i+1
+		} else if kind == "FULLTEXT" {
+			return "", "", false
+		}
+	default:
+		return "", "", false
+	}
+	i = skipIfExists(tokens, i)
+	if i >= len(tokens) {
+		return "", "", false
+	}
+	return kind, name(tokens[i]), true
+}
+
+// filtered checks whether a statement of the group has a WHERE clause
+func filtered(statements []Statement, group int) bool {
+	for _, s := range statements {
+		if s.Group != group {
+			continue
+		}
+		for _, t := range s.Tokens {
+			if upper(t) == "WHERE" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// usesPipe checks whether the statement uses the piped input by $-
+func usesPipe(s Statement) bool {
+	for _, t := range s.Tokens {
+		if t.Kind == Variable && t.Text == "$-" {
+			return true
+		}
+	}
+	return false
+}
+
+// unfilteredVariable checks whether the statement uses a variable assigned by a statement without
+// WHERE, the groups of the assignments are keyed by the variables. The $names not assigned are
+// parameters, which are values rather than the results of queries.
+func unfilteredVariable(s Statement, statements []Statement, assignments map[string]int) bool {
+	for _, t := range s.Tokens {
+		if t.Kind != Parameter {
+			continue
+		}
+		if group, ok := assignments[t.Text]; ok && !filtered(statements, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// Destructive returns the destructive statements in the text
+func Destructive(text string) []Danger {
+	statements := Classify(text)
+	var dangers []Danger
+	assignments := make(map[string]int)
+	for _, s := range statements {
+		if s.Assigns != "" {
+			assignments[s.Assigns] = s.Group
+		}
+		tokens := s.Tokens
+		if len(tokens) == 0 {
+			continue
+		}
+		switch upper(tokens[0]) {
+		case "DROP":
+			if kind, name, ok := dropped(tokens); ok {
+				dangers = append(dangers, Danger{Head: "DROP " + kind, Kind: kind, Name: name})
+			}
+		case "CLEAR":
+			if kind, name, ok := dropped(tokens); ok && kind == "SPACE" {
+				dangers = append(dangers, Danger{Head: "CLEAR SPACE", Kind: kind, Name: name})
+			}
+		case "DELETE":
+			// The vertices or edges returned by a query without WHERE, which may be all of them,
+			// either piped or assigned to a variable by a previous statement
+			if len(tokens) > 1 && ((s.Piped || usesPipe(s)) && !filtered(statements, s.Group) ||
+				unfilteredVariable(s, statements, assignments)) {
+				kind := upper(tokens[1])
+				dangers = append(dangers, Danger{Head: "DELETE " + kind, Kind: kind})
+			}
+		case "BALANCE":
+			if len(tokens) > 3 && upper(tokens[1]) == "DATA" && upper(tokens[2]) == "REMOVE" {
+				var hosts strings.Builder
+				for _, t := range tokens[3:] {
+					hosts.WriteString(t.Text)
+				}
+				dangers = append(dangers, Danger{Head: "BALANCE DATA REMOVE", Kind: "HOSTS", Name: hosts.String()})
+			}
+		}
+	}
+	return dangers
+}
@@ -0,0 +1,66 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package ngql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDestructive(t *testing.T) {
+	cases := []struct {
+		input string
+		want  []Danger
+	}{
+		{"SHOW SPACES", nil},
+		{"DROP SPACE nba", []Danger{{Head: "DROP SPACE", Kind: "SPACE", Name: "nba"}}},
+		{"DROP SPACE IF EXISTS `my space`", []Danger{{Head: "DROP SPACE", Kind: "SPACE", Name: "my space"}}},
+		{"DROP TAG INDEX i", []Danger{{Head: "DROP TAG INDEX", Kind: "TAG INDEX", Name: "i"}}},
+		{"DROP EDGE e", []Danger{{Head: "DROP EDGE", Kind: "EDGE", Name: "e"}}},
+		{"DROP FULLTEXT INDEX f", []Danger{{Head: "DROP FULLTEXT INDEX", Kind: "FULLTEXT INDEX", Name: "f"}}},
+		{"DROP USER bob", nil},
+		{"CLEAR SPACE nba", []Danger{{Head: "CLEAR SPACE", Kind: "SPACE", Name: "nba"}}},
+		{"USE s; DROP TAG t; DROP EDGE e", []Danger{
+			{Head: "DROP TAG", Kind: "TAG", Name: "t"},
+			{Head: "DROP EDGE", Kind: "EDGE", Name: "e"},
+		}},
+		// The statements profiled or in the comments
+		{"PROFILE DROP TAG t", []Danger{{Head: "DROP TAG", Kind: "TAG", Name: "t"}}},
+		{"SHOW TAGS # DROP TAG t", nil},
+		// The deletions of the specified vertices, or the filtered results
+		{"DELETE VERTEX 1, 2", nil},
+		{"DELETE VERTEX $p", nil},
+		{"LOOKUP ON t YIELD id(vertex) AS id | DELETE VERTEX $-.id", []Danger{{Head: "DELETE VERTEX", Kind: "VERTEX"}}},
+		{"LOOKUP ON t WHERE t.a > 1 YIELD id(vertex) AS id | DELETE VERTEX $-.id", nil},
+		{"$v = LOOKUP ON t YIELD id(vertex) AS id; DELETE VERTEX $v.id", []Danger{{Head: "DELETE VERTEX", Kind: "VERTEX"}}},
+		{"$v = LOOKUP ON t WHERE t.a > 1 YIELD id(vertex) AS id; DELETE VERTEX $v.id", nil},
+		{"GO FROM 1 OVER e YIELD src(edge) AS s, dst(edge) AS d | DELETE EDGE e $-.s -> $-.d",
+			[]Danger{{Head: "DELETE EDGE", Kind: "EDGE"}}},
+		{"BALANCE DATA REMOVE 192.168.0.1:9779", []Danger{{Head: "BALANCE DATA REMOVE", Kind: "HOSTS", Name: "192.168.0.1:9779"}}},
+		{"BALANCE DATA", nil},
+	}
+	for _, c := range cases {
+		if got := Destructive(c.input); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Destructive(%q) = %+v, want %+v", c.input, got, c.want)
+		}
+	}
+}
+
+func TestConfirmation(t *testing.T) {
+	cases := []struct {
+		danger Danger
+		want   string
+	}{
+		{Danger{Head: "DROP SPACE", Kind: "SPACE", Name: "nba"}, "nba"},
+		{Danger{Head: "DELETE VERTEX", Kind: "VERTEX"}, "DELETE"},
+		{Danger{Head: "BALANCE DATA REMOVE", Kind: "HOSTS", Name: "h:9779"}, "BALANCE"},
+	}
+	for _, c := range cases {
+		if got := c.danger.Confirmation(); got != c.want {
+			t.Errorf("%+v.Confirmation() = %q, want %q", c.danger, got, c.want)
+		}
+	}
+}
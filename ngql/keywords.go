@@ -0,0 +1,53 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package ngql
+
+import (
+	"strings"
+)
+
+// Keywords are the keywords of nGQL, which are completed and highlighted in the prompt
+var Keywords = []string{
+	"GO", "AS", "TO", "OR", "AND", "XOR", "USE", "SET", "FROM",
+	"WHERE", "MATCH", "INSERT", "YIELD", "RETURN", "DESCRIBE",
+	"DESC", "VERTEX", "EDGE", "EDGES", "UPDATE", "UPSERT",
+	"WHEN", "DELETE", "FIND", "LOOKUP", "ALTER", "STEPS", "OVER",
+	"UPTO", "REVERSELY", "INDEX", "INDEXES", "REBUILD", "BOOL",
+	"INT8", "INT16", "INT32", "INT64", "INT", "FLOAT", "DOUBLE",
+	"STRING", "FIXED_STRING", "TIMESTAMP", "DATE", "DATETIME",
+	"TAG", "TAGS", "UNION", "INTERSECT", "MINUS", "NO", "OVERWRITE",
+	"SHOW", "ADD", "CREATE", "DROP", "REMOVE", "IF", "NOT", "EXISTS",
+	"WITH", "CHANGE", "GRANT", "REVOKE", "ON", "BY", "IN", "DOWNLOAD",
+	"GET", "OF", "ORDER", "INGEST", "COMPACT", "FLUSH", "SUBMIT",
+	"ASC", "DISTINCT", "FETCH", "PROP", "BALANCE", "STOP", "LIMIT",
+	"OFFSET", "IS", "NULL", "RECOVER", "EXPLAIN", "PROFILE", "FORMAT",
+	"CASE", "MATCH", "UNWIND", "SKIP", "SIGN",
+	"HOSTS", "SPACE", "SPACES", "VALUES", "USER", "USERS", "PASSWORD",
+	"ROLE", "ROLES", "GOD", "ADMIN", "DBA", "GUEST", "GROUP", "PARTITION_NUM",
+	"REPLICA_FACTOR", "VID_SIZE", "CHARSET", "COLLATE", "COLLATION", "ALL",
+	"LEADER", "UUID", "DATA", "SNAPSHOT", "SNAPSHOTS", "OFFLINE", "ACCOUNT",
+	"JOBS", "JOB", "COUNT", "COUNT_DISTINCT", "SUM", "AVG", "MAX", "MIN",
+	"STD", "BIT_AND", "BIT_OR", "BIT_XOR", "PATH", "BIDIRECT", "STATUS", "FORCE",
+	"PART", "PARTS", "DEFAULT", "HDFS", "CONFIGS", "TTL_DURATION", "TTL_COL",
+	"GRAPH", "META", "STORAGE", "SHORTEST", "OUT", "BOTH", "SUBGRAPH", "CONTAINS",
+	"TRUE", "FALSE", "THEN", "ELSE", "END", "STARTS", "ENDS", "WITH",
+	"<-", "->", "_id", "_type", "_src", "_dst", "_rank", "$$", "$^", "$-",
+	/* ".", ",", ":", ";", "@", "+", "-", "*", "/", "%", "!", "^", "<", "<=",
+	   ">", ">=", "==", "!=", "||", "&&", "|", "=", "(", ")", "[", "]" */
+}
+
+var keywordSet = func() map[string]bool {
+	set := make(map[string]bool, len(Keywords))
+	for _, k := range Keywords {
+		set[k] = true
+	}
+	return set
+}()
+
+// IsKeyword checks whether the word is a keyword in any case, except the lower case ones like _id
+func IsKeyword(word string) bool {
+	return keywordSet[word] || keywordSet[strings.ToUpper(word)]
+}
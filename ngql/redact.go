@@ -0,0 +1,108 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package ngql
+
+import (
+	"sort"
+	"strings"
+)
+
+// RedactedSecret replaces the secrets in the messages, the quotes of the literals are kept
+const RedactedSecret = "***"
+
+// The statements whose string literals are all credentials, i.e. CHANGE PASSWORD bob FROM 'old' TO 'new'
+var secretHeads = map[string]bool{
+	"CREATE USER": true, "ALTER USER": true, "CHANGE PASSWORD": true, "SIGN IN": true,
+}
+
+// secretTokens returns the string literals which are credentials, the statements split
+// across lines are covered by the literals after PASSWORD
+func secretTokens(text string) []Token {
+	positions := make(map[int]Token)
+	for _, s := range Classify(text) {
+		if !secretHeads[s.Head] {
+			continue
+		}
+		for _, t := range s.Tokens {
+			if t.Kind == String {
+				positions[t.Pos] = t
+			}
+		}
+	}
+	tokens := Significant(Tokenize(text))
+	for i := 1; i < len(tokens); i++ {
+		if tokens[i].Kind == String && upper(tokens[i-1]) == "PASSWORD" {
+			positions[tokens[i].Pos] = tokens[i]
+		}
+	}
+	var secrets []Token
+	for _, t := range positions {
+		secrets = append(secrets, t)
+	}
+	sort.Slice(secrets, func(i, j int) bool {
+		return secrets[i].Pos < secrets[j].Pos
+	})
+	return secrets
+}
+
+// unquote returns the content of a string literal, which may be unterminated
+func unquote(literal string) string {
+	content := literal[1:]
+	if len(content) > 0 && content[len(content)-1] == literal[0] {
+		content = content[:len(content)-1]
+	}
+	return content
+}
+
+// Secrets returns the string literals of the credentials in the text with the quotes, i.e. 'password'
+func Secrets(text string) []string {
+	var secrets []string
+	for _, t := range secretTokens(text) {
+		if unquote(t.Text) != "" {
+			secrets = append(secrets, t.Text)
+		}
+	}
+	return secrets
+}
+
+// Redact masks the credentials in the text, i.e. CREATE USER bob WITH PASSWORD '***'
+func Redact(text string) string {
+	secrets := secretTokens(text)
+	if len(secrets) == 0 {
+		return text
+	}
+	var b strings.Builder
+	last := 0
+	for _, t := range secrets {
+		b.WriteString(text[last:t.Pos])
+		quote := t.Text[:1]
+		b.WriteString(quote + RedactedSecret + quote)
+		last = t.Pos + len(t.Text)
+	}
+	b.WriteString(text[last:])
+	return b.String()
+}
+
+// HasRedacted checks whether the credentials in the text are masked, i.e. the statement recalled
+// from the history, which would set the password to '***' if it's executed
+func HasRedacted(text string) bool {
+	for _, t := range secretTokens(text) {
+		if unquote(t.Text) == RedactedSecret {
+			return true
+		}
+	}
+	return false
+}
+
+// RedactMessage masks the secrets of a statement in a message about it, i.e. an error message.
+// Only the literals with the quotes are masked, the same text elsewhere in the message is kept.
+func RedactMessage(msg string, secrets []string) string {
+	for _, s := range secrets {
+		quote := s[:1]
+		msg = strings.ReplaceAll(msg, s, quote+RedactedSecret+quote)
+	}
+	return msg
+}
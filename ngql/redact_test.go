@@ -0,0 +1,93 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package ngql
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRedact(t *testing.T) {
+	cases := []struct {
+		input string
+		want  string
+	}{
+		{"SHOW USERS", "SHOW USERS"},
+		{"CREATE USER bob WITH PASSWORD 'secret'", "CREATE USER bob WITH PASSWORD '***'"},
+		{`CREATE USER IF NOT EXISTS bob WITH PASSWORD "secret"`, `CREATE USER IF NOT EXISTS bob WITH PASSWORD "***"`},
+		{"ALTER USER bob WITH PASSWORD 'a\\'b'", "ALTER USER bob WITH PASSWORD '***'"},
+		{"CHANGE PASSWORD bob FROM 'old' TO 'new'", "CHANGE PASSWORD bob FROM '***' TO '***'"},
+		{"SIGN IN TEXT SERVICE (127.0.0.1:9200, HTTP, 'user', 'pass')", "SIGN IN TEXT SERVICE (127.0.0.1:9200, HTTP, '***', '***')"},
+		{"USE s; CREATE USER bob WITH PASSWORD 'p'; YIELD 'p'", "USE s; CREATE USER bob WITH PASSWORD '***'; YIELD 'p'"},
+		// The literal after PASSWORD is masked even if the statement is split across lines
+		{"WITH PASSWORD 'p'", "WITH PASSWORD '***'"},
+		{"CREATE USER bob WITH PASSWORD 'open", "CREATE USER bob WITH PASSWORD '***'"},
+		{"INSERT VERTEX t(password) VALUES 1:('kept')", "INSERT VERTEX t(password) VALUES 1:('kept')"},
+	}
+	for _, c := range cases {
+		if got := Redact(c.input); got != c.want {
+			t.Errorf("Redact(%q) = %q, want %q", c.input, got, c.want)
+		}
+	}
+}
+
+func TestSecrets(t *testing.T) {
+	cases := []struct {
+		input string
+		want  []string
+	}{
+		{"SHOW USERS", nil},
+		{"CHANGE PASSWORD bob FROM 'old' TO \"new\"", []string{"'old'", `"new"`}},
+		{"CREATE USER bob WITH PASSWORD ''", nil},
+	}
+	for _, c := range cases {
+		if got := Secrets(c.input); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Secrets(%q) = %q, want %q", c.input, got, c.want)
+		}
+	}
+}
+
+func TestHasRedacted(t *testing.T) {
+	cases := []struct {
+		input string
+		want  bool
+	}{
+		{"CREATE USER bob WITH PASSWORD '***'", true},
+		{"CREATE USER bob WITH PASSWORD 'secret'", false},
+		{"YIELD '***'", false},
+	}
+	for _, c := range cases {
+		if got := HasRedacted(c.input); got != c.want {
+			t.Errorf("HasRedacted(%q) = %t, want %t", c.input, got, c.want)
+		}
+	}
+}
+
+func TestRedactMessage(t *testing.T) {
+	cases := []struct {
+		stmt string
+		msg  string
+		want string
+	}{
+		{
+			"CREATE USER bob WITH PASSWORD 'pa'",
+			"SyntaxError: syntax error near `'pa'`",
+			"SyntaxError: syntax error near `'***'`",
+		},
+		// The same text out of the literal is kept
+		{
+			"CREATE USER bob WITH PASSWORD 'e'",
+			"the user exists, near `'e'`",
+			"the user exists, near `'***'`",
+		},
+		{"SHOW USERS", "Permission denied", "Permission denied"},
+	}
+	for _, c := range cases {
+		if got := RedactMessage(c.msg, Secrets(c.stmt)); got != c.want {
+			t.Errorf("RedactMessage(%q, %q) = %q, want %q", c.msg, c.stmt, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,184 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package ngql
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type TokenKind int
+
+const (
+	Space TokenKind = iota
+	Comment
+	// Word is a keyword or an identifier
+	Word
+	// QuotedName is an identifier enclosed in backquotes
+	QuotedName
+	String
+	Number
+	// Parameter is a parameter defined by :param or a variable assigned by `$var = ...`
+	Parameter
+	// Variable is one of the input and property references $-, $^ and $$
+	Variable
+	Punct
+)
+
+// Token is a lexical unit of nGQL, the texts of the tokens make up the input exactly,
+// so an unterminated string or comment extends to the end
+type Token struct {
+	Kind TokenKind
+	Text string
+	// the byte offset in the input
+	Pos int
+}
+
+// The punctuations of two characters, the others are single characters
+var operators = []string{"->", "<-", "==", "!=", "<=", ">=", "||", "&&", "=~"}
+
+func isWordRune(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// scanQuoted returns the end of the quoted text starting at i, backslashes escape the next character
+func scanQuoted(s string, i int) int {
+	quote := s[i]
+	for j := i + 1; j < len(s); j++ {
+		switch s[j] {
+		case '\\':
+			j++
+		case quote:
+			return j + 1
+		}
+	}
+	return len(s)
+}
+
+func scanWhile(s string, i int, f func(r rune) bool) int {
+	for i < len(s) {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if !f(r) {
+			break
+		}
+		i += size
+	}
+	return i
+}
+
+func scanNumber(s string, i int) int {
+	if strings.HasPrefix(s[i:], "0x") || strings.HasPrefix(s[i:], "0X") {
+		return scanWhile(s, i+2, func(r rune) bool {
+			return unicode.Is(unicode.ASCII_Hex_Digit, r)
+		})
+	}
+	j := scanWhile(s, i, unicode.IsDigit)
+	if j < len(s)-1 && s[j] == '.' && s[j+1] >= '0' && s[j+1] <= '9' {
+		j = scanWhile(s, j+1, unicode.IsDigit)
+	}
+	if j < len(s) && (s[j] == 'e' || s[j] == 'E') {
+		k := j + 1
+		if k < len(s) && (s[k] == '+' || s[k] == '-') {
+			k++
+		}
+		if k < len(s) && s[k] >= '0' && s[k] <= '9' {
+			j = scanWhile(s, k, unicode.IsDigit)
+		}
+	}
+	return j
+}
+
+// isDashComment checks whether the `--` at i starts a comment rather than a pattern
+// of MATCH, i.e. `(a)--(b)` or `(a)-->(b)`
+func isDashComment(s string, i int) bool {
+	if !strings.HasPrefix(s[i:], "--") {
+		return false
+	}
+	if next := i + 2; next < len(s) && strings.IndexByte(">([", s[next]) >= 0 {
+		return false
+	}
+	prev := strings.TrimRightFunc(s[:i], unicode.IsSpace)
+	return prev == "" || (prev[len(prev)-1] != ')' && prev[len(prev)-1] != ']')
+}
+
+// Tokenize splits the input into tokens, including the spaces and comments
+func Tokenize(s string) []Token {
+	var tokens []Token
+	for i := 0; i < len(s); {
+		kind, end := Punct, i+1
+		r, size := utf8.DecodeRuneInString(s[i:])
+		rest := s[i:]
+		switch {
+		case unicode.IsSpace(r):
+			kind, end = Space, scanWhile(s, i, unicode.IsSpace)
+		case r == '#' || strings.HasPrefix(rest, "//") || isDashComment(s, i):
+			kind, end = Comment, len(s)
+			if n := strings.IndexByte(rest, '\n'); n >= 0 {
+				end = i + n
+			}
+		case strings.HasPrefix(rest, "/*"):
+			kind, end = Comment, len(s)
+			if n := strings.Index(rest[2:], "*/"); n >= 0 {
+				end = i + 2 + n + 2
+			}
+		case r == '\'' || r == '"':
+			kind, end = String, scanQuoted(s, i)
+		case r == '`':
+			kind, end = QuotedName, scanQuoted(s, i)
+		case unicode.IsDigit(r):
+			kind, end = Number, scanNumber(s, i)
+		case r == '$' && len(rest) > 1 && (rest[1] == '-' || rest[1] == '^' || rest[1] == '$'):
+			kind, end = Variable, i+2
+		case r == '$' && len(rest) > 1 && isWordRune(rune(rest[1])):
+			kind, end = Parameter, scanWhile(s, i+1, isWordRune)
+		case isWordRune(r):
+			kind, end = Word, scanWhile(s, i, isWordRune)
+		default:
+			end = i + size
+			for _, op := range operators {
+				if strings.HasPrefix(rest, op) {
+					end = i + len(op)
+					break
+				}
+			}
+		}
+		tokens = append(tokens, Token{Kind: kind, Text: s[i:end], Pos: i})
+		i = end
+	}
+	return tokens
+}
+
+// Significant returns the tokens except the spaces and comments
+func Significant(tokens []Token) []Token {
+	var result []Token
+	for _, t := range tokens {
+		if t.Kind != Space && t.Kind != Comment {
+			result = append(result, t)
+		}
+	}
+	return result
+}
+
+// Split returns the statements in the text separated by the semicolons, which are not in
+// the strings or the comments. The statements are trimmed and the empty ones are skipped.
+func Split(text string) []string {
+	var statements []string
+	start := 0
+	add := func(end int) {
+		if stmt := strings.TrimSpace(text[start:end]); stmt != "" {
+			statements = append(statements, stmt)
+		}
+	}
+	for _, t := range Tokenize(text) {
+		if t.Kind == Punct && t.Text == ";" {
+			add(t.Pos)
+			start = t.Pos + len(t.Text)
+		}
+	}
+	add(len(text))
+	return statements
+}
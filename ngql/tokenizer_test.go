@@ -0,0 +1,91 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package ngql
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	type tk struct {
+		Kind TokenKind
+		Text string
+	}
+	cases := []struct {
+		input string
+		want  []tk
+	}{
+		{"", nil},
+		{"SHOW SPACES;", []tk{{Word, "SHOW"}, {Space, " "}, {Word, "SPACES"}, {Punct, ";"}}},
+		{`"a\"b" 'c'`, []tk{{String, `"a\"b"`}, {Space, " "}, {String, "'c'"}}},
+		{"'unterminated", []tk{{String, "'unterminated"}}},
+		{"`my tag`", []tk{{QuotedName, "`my tag`"}}},
+		{"1 2.5 1e10 0x1F", []tk{{Number, "1"}, {Space, " "}, {Number, "2.5"}, {Space, " "}, {Number, "1e10"}, {Space, " "}, {Number, "0x1F"}}},
+		{"$p $- $^ $$", []tk{{Parameter, "$p"}, {Space, " "}, {Variable, "$-"}, {Space, " "}, {Variable, "$^"}, {Space, " "}, {Variable, "$$"}}},
+		{"a->b<-c", []tk{{Word, "a"}, {Punct, "->"}, {Word, "b"}, {Punct, "<-"}, {Word, "c"}}},
+		{"x >= 1", []tk{{Word, "x"}, {Space, " "}, {Punct, ">="}, {Space, " "}, {Number, "1"}}},
+		{"# comment\nGO", []tk{{Comment, "# comment"}, {Space, "\n"}, {Word, "GO"}}},
+		{"GO // c", []tk{{Word, "GO"}, {Space, " "}, {Comment, "// c"}}},
+		{"-- c", []tk{{Comment, "-- c"}}},
+		{"/* a\nb */GO", []tk{{Comment, "/* a\nb */"}, {Word, "GO"}}},
+		{"/* open", []tk{{Comment, "/* open"}}},
+		// The dashes of the MATCH patterns are not comments
+		{"(a)--(b)", []tk{{Punct, "("}, {Word, "a"}, {Punct, ")"}, {Punct, "-"}, {Punct, "-"}, {Punct, "("}, {Word, "b"}, {Punct, ")"}}},
+		{"(a)-->(b)", []tk{{Punct, "("}, {Word, "a"}, {Punct, ")"}, {Punct, "-"}, {Punct, "->"}, {Punct, "("}, {Word, "b"}, {Punct, ")"}}},
+		{"名字", []tk{{Word, "名字"}}},
+	}
+	for _, c := range cases {
+		tokens := Tokenize(c.input)
+		var got []tk
+		var text strings.Builder
+		for _, token := range tokens {
+			if token.Pos != text.Len() {
+				t.Errorf("Tokenize(%q): token %q at %d, want %d", c.input, token.Text, token.Pos, text.Len())
+			}
+			text.WriteString(token.Text)
+			got = append(got, tk{token.Kind, token.Text})
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Tokenize(%q) = %v, want %v", c.input, got, c.want)
+		}
+		if text.String() != c.input {
+			t.Errorf("Tokenize(%q) makes up %q", c.input, text.String())
+		}
+	}
+}
+
+func TestSignificant(t *testing.T) {
+	tokens := Significant(Tokenize("GO /* c */ FROM # c\n 1"))
+	var texts []string
+	for _, token := range tokens {
+		texts = append(texts, token.Text)
+	}
+	if want := []string{"GO", "FROM", "1"}; !reflect.DeepEqual(texts, want) {
+		t.Errorf("Significant = %v, want %v", texts, want)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	cases := []struct {
+		text string
+		want []string
+	}{
+		{"", nil},
+		{"SHOW TAGS", []string{"SHOW TAGS"}},
+		{" USE s; CREATE TAG t();; ", []string{"USE s", "CREATE TAG t()"}},
+		// The semicolons in the strings and the comments don't separate the statements
+		{`YIELD "a;b"; YIELD 'c;'`, []string{`YIELD "a;b"`, `YIELD 'c;'`}},
+		{"CREATE TAG t() /* a; b */; SHOW TAGS # c; d", []string{"CREATE TAG t() /* a; b */", "SHOW TAGS # c; d"}},
+		{"CREATE TAG `a;b`(); SHOW TAGS", []string{"CREATE TAG `a;b`()", "SHOW TAGS"}},
+	}
+	for _, c := range cases {
+		if got := Split(c.text); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Split(%q) = %q, want %q", c.text, got, c.want)
+		}
+	}
+}
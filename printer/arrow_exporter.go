@@ -0,0 +1,423 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package printer
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/ipc"
+	"github.com/apache/arrow/go/v13/arrow/memory"
+	"github.com/apache/arrow/go/v13/parquet"
+	"github.com/apache/arrow/go/v13/parquet/pqarrow"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+)
+
+// Rows are converted and written in batches of this size
+const arrowBatchSize = 4096
+
+// Nested types of the graph values
+var (
+	arrowPropsType = arrow.MapOf(arrow.BinaryTypes.String, arrow.BinaryTypes.String)
+
+	arrowVertexType = arrow.StructOf(
+		arrow.Field{Name: "vid", Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: "tags", Type: arrow.ListOf(arrow.StructOf(
+			arrow.Field{Name: "name", Type: arrow.BinaryTypes.String},
+			arrow.Field{Name: "properties", Type: arrowPropsType, Nullable: true},
+		)), Nullable: true},
+	)
+
+	arrowEdgeType = arrow.StructOf(
+		arrow.Field{Name: "src", Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: "dst", Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: "name", Type: arrow.BinaryTypes.String},
+		arrow.Field{Name: "rank", Type: arrow.PrimitiveTypes.Int64},
+		arrow.Field{Name: "properties", Type: arrowPropsType, Nullable: true},
+	)
+
+	arrowPathType = arrow.StructOf(
+		arrow.Field{Name: "nodes", Type: arrow.ListOf(arrowVertexType), Nullable: true},
+		arrow.Field{Name: "relationships", Type: arrow.ListOf(arrowEdgeType), Nullable: true},
+	)
+
+	arrowDurationType = arrow.StructOf(
+		arrow.Field{Name: "months", Type: arrow.PrimitiveTypes.Int32},
+		arrow.Field{Name: "seconds", Type: arrow.PrimitiveTypes.Int64},
+		arrow.Field{Name: "microseconds", Type: arrow.PrimitiveTypes.Int32},
+	)
+
+	arrowDateTimeType = &arrow.TimestampType{Unit: arrow.Microsecond}
+)
+
+// ArrowExporter writes the result set of the next statement to a parquet or an arrow IPC file
+type ArrowExporter struct {
+	format   string
+	filename string
+}
+
+func NewArrowExporter(format, filename string) (*ArrowExporter, error) {
+	switch format {
+	case "parquet", "arrow":
+	default:
+		return nil, fmt.Errorf("unsupported export format %s", format)
+	}
+	return &ArrowExporter{
+		format:   format,
+		filename: filename,
+	}, nil
+}
+
+func (e *ArrowExporter) Filename() string {
+	return e.filename
+}
+
+// recordWriter is implemented by both ipc.FileWriter and pqarrow.FileWriter
+type recordWriter interface {
+	Write(rec arrow.Record) error
+	Close() error
+}
+
+func (e *ArrowExporter) Export(res *nebula.ResultSet) error {
+	schema, err := inferArrowSchema(res)
+	if err != nil {
+		return err
+	}
+
+	fd, err := os.OpenFile(e.filename, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+
+	var w recordWriter
+	mem := memory.NewGoAllocator()
+	if e.format == "parquet" {
+		w, err = pqarrow.NewFileWriter(schema, fd, parquet.NewWriterProperties(), pqarrow.NewArrowWriterProperties(pqarrow.WithAllocator(mem)))
+	} else {
+		w, err = ipc.NewFileWriter(fd, ipc.WithSchema(schema), ipc.WithAllocator(mem))
+	}
+	if err != nil {
+		return err
+	}
+
+	builder := array.NewRecordBuilder(mem, schema)
+	defer builder.Release()
+
+	flush := func() error {
+		rec := builder.NewRecord()
+		defer rec.Release()
+		if rec.NumRows() == 0 {
+			return nil
+		}
+		return w.Write(rec)
+	}
+
+	numRows := res.GetRowSize()
+	for i := 0; i < numRows; i++ {
+		record, err := res.GetRowValuesByIndex(i)
+		if err != nil {
+			w.Close()
+			return err
+		}
+		for j := range schema.Fields() {
+			val, err := record.GetValueByIndex(j)
+			if err != nil {
+				w.Close()
+				return err
+			}
+			appendArrowValue(builder.Field(j), *val)
+		}
+		if (i+1)%arrowBatchSize == 0 {
+			if err := flush(); err != nil {
+				w.Close()
+				return err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// inferArrowSchema infers the type of every column from the kinds of its values
+func inferArrowSchema(res *nebula.ResultSet) (*arrow.Schema, error) {
+	var fields []arrow.Field
+	for _, name := range res.GetColNames() {
+		values, err := res.GetValuesByColName(name)
+		if err != nil {
+			return nil, err
+		}
+		column := make([]nebula.ValueWrapper, 0, len(values))
+		for _, v := range values {
+			column = append(column, *v)
+		}
+		fields = append(fields, arrow.Field{Name: name, Type: inferArrowType(column), Nullable: true})
+	}
+	return arrow.NewSchema(fields, nil), nil
+}
+
+// inferArrowType returns the arrow type of the values, nulls are ignored.
+// Ints mixed with floats are stored as floats, other mixed kinds are stored as strings.
+func inferArrowType(values []nebula.ValueWrapper) arrow.DataType {
+	kind := ""
+	for _, v := range values {
+		if v.IsNull() || v.IsEmpty() {
+			continue
+		}
+		k := v.GetType()
+		if k == "set" {
+			k = "list"
+		}
+		switch {
+		case kind == "":
+			kind = k
+		case kind == k:
+		case (kind == "int" && k == "float") || (kind == "float" && k == "int"):
+			kind = "float"
+		default:
+			return arrow.BinaryTypes.String
+		}
+	}
+
+	switch kind {
+	case "bool":
+		return arrow.FixedWidthTypes.Boolean
+	case "int":
+		return arrow.PrimitiveTypes.Int64
+	case "float":
+		return arrow.PrimitiveTypes.Float64
+	case "date":
+		return arrow.FixedWidthTypes.Date32
+	case "time":
+		return arrow.FixedWidthTypes.Time64us
+	case "datetime":
+		return arrowDateTimeType
+	case "duration":
+		return arrowDurationType
+	case "vertex":
+		return arrowVertexType
+	case "edge":
+		return arrowEdgeType
+	case "path":
+		return arrowPathType
+	case "list":
+		var elems []nebula.ValueWrapper
+		for _, v := range values {
+			elems = append(elems, listElements(v)...)
+		}
+		return arrow.ListOf(inferArrowType(elems))
+	case "map":
+		var items []nebula.ValueWrapper
+		for _, v := range values {
+			m, err := v.AsMap()
+			if err != nil {
+				continue
+			}
+			for _, item := range m {
+				items = append(items, item)
+			}
+		}
+		return arrow.MapOf(arrow.BinaryTypes.String, inferArrowType(items))
+	default:
+		// string, geography and the columns with only nulls
+		return arrow.BinaryTypes.String
+	}
+}
+
+func listElements(v nebula.ValueWrapper) []nebula.ValueWrapper {
+	if v.IsSet() {
+		elems, _ := v.AsDedupList()
+		return elems
+	}
+	elems, _ := v.AsList()
+	return elems
+}
+
+func vidString(vid nebula.ValueWrapper) string {
+	return plainString(&vid)
+}
+
+func appendArrowProps(b *array.MapBuilder, props map[string]*nebula.ValueWrapper) {
+	b.Append(true)
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		b.KeyBuilder().(*array.StringBuilder).Append(k)
+		if v := props[k]; v.IsNull() || v.IsEmpty() {
+			b.ItemBuilder().AppendNull()
+		} else {
+			b.ItemBuilder().(*array.StringBuilder).Append(plainString(v))
+		}
+	}
+}
+
+func appendArrowVertex(b *array.StructBuilder, node *nebula.Node) {
+	b.Append(true)
+	b.FieldBuilder(0).(*array.StringBuilder).Append(vidString(node.GetID()))
+	tags := b.FieldBuilder(1).(*array.ListBuilder)
+	tags.Append(true)
+	tag := tags.ValueBuilder().(*array.StructBuilder)
+	for _, name := range node.GetTags() {
+		tag.Append(true)
+		tag.FieldBuilder(0).(*array.StringBuilder).Append(name)
+		props, err := node.Properties(name)
+		if err != nil {
+			tag.FieldBuilder(1).AppendNull()
+			continue
+		}
+		appendArrowProps(tag.FieldBuilder(1).(*array.MapBuilder), props)
+	}
+}
+
+func appendArrowEdge(b *array.StructBuilder, rel *nebula.Relationship) {
+	b.Append(true)
+	b.FieldBuilder(0).(*array.StringBuilder).Append(vidString(rel.GetSrcVertexID()))
+	b.FieldBuilder(1).(*array.StringBuilder).Append(vidString(rel.GetDstVertexID()))
+	b.FieldBuilder(2).(*array.StringBuilder).Append(rel.GetEdgeName())
+	b.FieldBuilder(3).(*array.Int64Builder).Append(rel.GetRanking())
+	appendArrowProps(b.FieldBuilder(4).(*array.MapBuilder), rel.Properties())
+}
+
+func appendArrowStruct(b *array.StructBuilder, v nebula.ValueWrapper) {
+	switch {
+	case v.IsVertex():
+		node, err := v.AsNode()
+		if err != nil {
+			b.AppendNull()
+			return
+		}
+		appendArrowVertex(b, node)
+	case v.IsEdge():
+		rel, err := v.AsRelationship()
+		if err != nil {
+			b.AppendNull()
+			return
+		}
+		appendArrowEdge(b, rel)
+	case v.IsPath():
+		path, err := v.AsPath()
+		if err != nil {
+			b.AppendNull()
+			return
+		}
+		b.Append(true)
+		nodes := b.FieldBuilder(0).(*array.ListBuilder)
+		nodes.Append(true)
+		for _, node := range path.GetNodes() {
+			appendArrowVertex(nodes.ValueBuilder().(*array.StructBuilder), node)
+		}
+		rels := b.FieldBuilder(1).(*array.ListBuilder)
+		rels.Append(true)
+		for _, rel := range path.GetRelationships() {
+			appendArrowEdge(rels.ValueBuilder().(*array.StructBuilder), rel)
+		}
+	case v.IsDuration():
+		d, err := v.AsDuration()
+		if err != nil {
+			b.AppendNull()
+			return
+		}
+		b.Append(true)
+		b.FieldBuilder(0).(*array.Int32Builder).Append(d.GetMonths())
+		b.FieldBuilder(1).(*array.Int64Builder).Append(d.GetSeconds())
+		b.FieldBuilder(2).(*array.Int32Builder).Append(d.GetMicroseconds())
+	default:
+		b.AppendNull()
+	}
+}
+
+// appendArrowValue appends the value to the builder of the inferred type,
+// a value that can not be converted is appended as null
+func appendArrowValue(b array.Builder, v nebula.ValueWrapper) {
+	if v.IsNull() || v.IsEmpty() {
+		b.AppendNull()
+		return
+	}
+	switch b := b.(type) {
+	case *array.StringBuilder:
+		b.Append(plainString(&v))
+	case *array.BooleanBuilder:
+		if x, err := v.AsBool(); err == nil {
+			b.Append(x)
+		} else {
+			b.AppendNull()
+		}
+	case *array.Int64Builder:
+		if x, err := v.AsInt(); err == nil {
+			b.Append(x)
+		} else {
+			b.AppendNull()
+		}
+	case *array.Float64Builder:
+		if x, err := v.AsInt(); err == nil {
+			b.Append(float64(x))
+		} else if x, err := v.AsFloat(); err == nil {
+			b.Append(x)
+		} else {
+			b.AppendNull()
+		}
+	case *array.Date32Builder:
+		if d, err := v.AsDate(); err == nil {
+			t := time.Date(int(d.GetYear()), time.Month(d.GetMonth()), int(d.GetDay()), 0, 0, 0, 0, time.UTC)
+			b.Append(arrow.Date32FromTime(t))
+		} else {
+			b.AppendNull()
+		}
+	case *array.Time64Builder:
+		// String returns the local time calculated with the timezone of graph service
+		if t, err := time.Parse("15:04:05.000000", v.String()); err == nil {
+			midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+			b.Append(arrow.Time64(t.Sub(midnight).Microseconds()))
+		} else {
+			b.AppendNull()
+		}
+	case *array.TimestampBuilder:
+		if t, err := time.Parse("2006-01-02T15:04:05.000000", v.String()); err == nil {
+			b.Append(arrow.Timestamp(t.UnixMicro()))
+		} else {
+			b.AppendNull()
+		}
+	case *array.MapBuilder:
+		m, err := v.AsMap()
+		if err != nil {
+			b.AppendNull()
+			return
+		}
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b.Append(true)
+		for _, k := range keys {
+			b.KeyBuilder().(*array.StringBuilder).Append(k)
+			appendArrowValue(b.ItemBuilder(), m[k])
+		}
+	case *array.ListBuilder:
+		if !v.IsList() && !v.IsSet() {
+			b.AppendNull()
+			return
+		}
+		b.Append(true)
+		for _, elem := range listElements(v) {
+			appendArrowValue(b.ValueBuilder(), elem)
+		}
+	case *array.StructBuilder:
+		appendArrowStruct(b, v)
+	default:
+		b.AppendNull()
+	}
+}
@@ -0,0 +1,139 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package printer
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/apache/arrow/go/v13/arrow"
+	"github.com/apache/arrow/go/v13/arrow/array"
+	"github.com/apache/arrow/go/v13/arrow/ipc"
+	"github.com/apache/arrow/go/v13/arrow/memory"
+	"github.com/apache/arrow/go/v13/parquet"
+	"github.com/apache/arrow/go/v13/parquet/pqarrow"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	ttypes "github.com/vesoft-inc/nebula-go/v3/nebula"
+)
+
+// vertex is a vertex of the string vid with one tag
+func vertex(vid, tag string, props map[string]*ttypes.Value) *ttypes.Vertex {
+	return &ttypes.Vertex{Vid: strValue(vid), Tags: []*ttypes.Tag{{Name: []byte(tag), Props: props}}}
+}
+
+func edge(src, dst, name string, rank int64, props map[string]*ttypes.Value) *ttypes.Edge {
+	return &ttypes.Edge{Src: strValue(src), Dst: strValue(dst), Type: 1, Name: []byte(name), Ranking: rank, Props: props}
+}
+
+func TestInferArrowType(t *testing.T) {
+	cases := []struct {
+		name   string
+		values []*ttypes.Value
+		want   arrow.DataType
+	}{
+		{"int", []*ttypes.Value{intValue(1), nullValue(), intValue(2)}, arrow.PrimitiveTypes.Int64},
+		// Ints mixed with floats are floats, the other mixed kinds are strings
+		{"int and float", []*ttypes.Value{intValue(1), floatValue(1.5)}, arrow.PrimitiveTypes.Float64},
+		{"int and string", []*ttypes.Value{intValue(1), strValue("a")}, arrow.BinaryTypes.String},
+		{"nulls", []*ttypes.Value{nullValue()}, arrow.BinaryTypes.String},
+		{"list", []*ttypes.Value{listValue(intValue(1)), listValue(floatValue(2))}, arrow.ListOf(arrow.PrimitiveTypes.Float64)},
+		{"vertex", []*ttypes.Value{{VVal: vertex("a", "player", nil)}}, arrowVertexType},
+		{"edge", []*ttypes.Value{{EVal: edge("a", "b", "follow", 0, nil)}}, arrowEdgeType},
+	}
+	for _, c := range cases {
+		var rows [][]*ttypes.Value
+		for _, v := range c.values {
+			rows = append(rows, []*ttypes.Value{v})
+		}
+		column, err := resultSet(t, []string{"c"}, rows...).GetValuesByColName("c")
+		if err != nil {
+			t.Fatal(err)
+		}
+		var values []nebula.ValueWrapper
+		for _, v := range column {
+			values = append(values, *v)
+		}
+		if got := inferArrowType(values); !arrow.TypeEqual(got, c.want) {
+			t.Errorf("%s: inferArrowType = %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestArrowExporter(t *testing.T) {
+	res := resultSet(t, []string{"id", "score", "name", "v"},
+		[]*ttypes.Value{intValue(1), floatValue(1.5), strValue("Tim"), {VVal: vertex("player100", "player", map[string]*ttypes.Value{"age": intValue(42)})}},
+		[]*ttypes.Value{intValue(2), intValue(3), nullValue(), {VVal: vertex("player101", "player", nil)}},
+	)
+	dir := t.TempDir()
+
+	path := filepath.Join(dir, "result.arrow")
+	e, err := NewArrowExporter("arrow", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Export(res); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	r, err := ipc.NewFileReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	wantSchema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+		{Name: "score", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+		{Name: "v", Type: arrowVertexType, Nullable: true},
+	}, nil)
+	if !r.Schema().Equal(wantSchema) {
+		t.Errorf("schema = %v, want %v", r.Schema(), wantSchema)
+	}
+	rec, err := r.Record(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.NumRows() != 2 {
+		t.Fatalf("%d rows exported, want 2", rec.NumRows())
+	}
+	scores := rec.Column(1).(*array.Float64)
+	names := rec.Column(2).(*array.String)
+	vids := rec.Column(3).(*array.Struct).Field(0).(*array.String)
+	if scores.Value(0) != 1.5 || scores.Value(1) != 3 || names.Value(0) != "Tim" || !names.IsNull(1) || vids.Value(1) != "player101" {
+		t.Errorf("exported record = %v", rec)
+	}
+
+	path = filepath.Join(dir, "result.parquet")
+	if e, err = NewArrowExporter("parquet", path); err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Export(res); err != nil {
+		t.Fatal(err)
+	}
+	pf, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer pf.Close()
+	table, err := pqarrow.ReadTable(context.Background(), pf, parquet.NewReaderProperties(nil), pqarrow.ArrowReadProperties{}, memory.NewGoAllocator())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer table.Release()
+	if table.NumRows() != 2 || table.NumCols() != 4 {
+		t.Errorf("parquet table has %d rows and %d columns, want 2 and 4", table.NumRows(), table.NumCols())
+	}
+
+	if _, err := NewArrowExporter("orc", path); err == nil {
+		t.Errorf("NewArrowExporter(orc) succeeded, want an error")
+	}
+}
@@ -0,0 +1,65 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package printer
+
+import (
+	"encoding/csv"
+	"fmt"
+	"os"
+)
+
+// CsvExporter writes the rows of a result set to a csv file one by one,
+// the quoting follows RFC 4180 by encoding/csv
+type CsvExporter struct {
+	fd       *os.File
+	writer   *csv.Writer
+	filename string
+	// whether the rows are still printed to the terminal
+	echo bool
+}
+
+func NewCsvExporter(filename string, echo bool) (*CsvExporter, error) {
+	fd, err := os.OpenFile(filename, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &CsvExporter{
+		fd:       fd,
+		writer:   csv.NewWriter(fd),
+		filename: filename,
+		echo:     echo,
+	}, nil
+}
+
+func (e *CsvExporter) Echo() bool {
+	return e.echo
+}
+
+func (e *CsvExporter) Filename() string {
+	return e.filename
+}
+
+func (e *CsvExporter) WriteHeader(colNames []string) error {
+	return e.writer.Write(colNames)
+}
+
+// WriteRow writes one record, the csv writer is buffered so the rows are
+// flushed to disk in chunks instead of being held all in memory
+func (e *CsvExporter) WriteRow(record []string) error {
+	return e.writer.Write(record)
+}
+
+func (e *CsvExporter) Close() error {
+	e.writer.Flush()
+	if err := e.writer.Error(); err != nil {
+		e.fd.Close()
+		return fmt.Errorf("write file %s failed, %s", e.filename, err.Error())
+	}
+	if err := e.fd.Close(); err != nil {
+		return fmt.Errorf("close file %s failed, %s", e.filename, err.Error())
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package printer
+
+import (
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	ttypes "github.com/vesoft-inc/nebula-go/v3/nebula"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+)
+
+// resultSet is the result of the columns and the rows of the values
+func resultSet(t *testing.T, columns []string, rows ...[]*ttypes.Value) *nebula.ResultSet {
+	data := &ttypes.DataSet{}
+	for _, col := range columns {
+		data.ColumnNames = append(data.ColumnNames, []byte(col))
+	}
+	for _, row := range rows {
+		data.Rows = append(data.Rows, &ttypes.Row{Values: row})
+	}
+	res, err := nebula.GenResultSet(&graph.ExecutionResponse{ErrorCode: ttypes.ErrorCode_SUCCEEDED, Data: data})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func intValue(i int64) *ttypes.Value {
+	return &ttypes.Value{IVal: &i}
+}
+
+func floatValue(f float64) *ttypes.Value {
+	return &ttypes.Value{FVal: &f}
+}
+
+func strValue(s string) *ttypes.Value {
+	return &ttypes.Value{SVal: []byte(s)}
+}
+
+func nullValue() *ttypes.Value {
+	null := ttypes.NullType___NULL__
+	return &ttypes.Value{NVal: &null}
+}
+
+func listValue(values ...*ttypes.Value) *ttypes.Value {
+	return &ttypes.Value{LVal: &ttypes.NList{Values: values}}
+}
+
+func TestExportCsv(t *testing.T) {
+	res := resultSet(t, []string{"name", "age", "note"},
+		[]*ttypes.Value{strValue("Tim Duncan"), intValue(42), strValue(`said "hi", left`)},
+		[]*ttypes.Value{strValue("line\nbreak"), nullValue(), floatValue(1.5)},
+	)
+	path := filepath.Join(t.TempDir(), "result.csv")
+	p := NewDataSetPrinter()
+	p.ExportCsv(path, false)
+	p.PrintDataSet(res)
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	// The strings are written without the nGQL quotes, and the quoting follows RFC 4180
+	records, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{
+		{"name", "age", "note"},
+		{"Tim Duncan", "42", `said "hi", left`},
+		{"line\nbreak", "__NULL__", "1.5"},
+	}
+	if !reflect.DeepEqual(records, want) {
+		t.Errorf("exported csv = %q, want %q", records, want)
+	}
+	if info, _ := os.Stat(path); info.Mode().Perm() != 0600 {
+		t.Errorf("mode of the csv file = %v, want 0600", info.Mode().Perm())
+	}
+
+	// The exporter applies to the next statement only
+	if p.exporter != nil {
+		t.Errorf("the exporter is kept after the statement")
+	}
+}
@@ -7,7 +7,6 @@ package printer
 
 import (
 	"fmt"
-	"os"
 	"strings"
 
 	"github.com/jedib0t/go-pretty/v6/table"
@@ -15,10 +14,16 @@ import (
 	nebula "github.com/vesoft-inc/nebula-go/v3"
 )
 
+// ResultExporter exports the whole result set of a statement to a file
+type ResultExporter interface {
+	Export(res *nebula.ResultSet) error
+	Filename() string
+}
+
 type DataSetPrinter struct {
-	writer   table.Writer
-	fd       *os.File
-	filename string
+	writer         table.Writer
+	exporter       *CsvExporter
+	resultExporter ResultExporter
 }
 
 func NewDataSetPrinter() DataSetPrinter {
@@ -29,14 +34,31 @@ func NewDataSetPrinter() DataSetPrinter {
 	}
 }
 
-func (p *DataSetPrinter) ExportCsv(filename string) {
-	fd, err := os.OpenFile(filename, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+// ExportCsv makes the result of the next statement written to the csv file,
+// the rows are not printed to the terminal if echo is false
+func (p *DataSetPrinter) ExportCsv(filename string, echo bool) {
+	exporter, err := NewCsvExporter(filename, echo)
 	if err != nil {
 		fmt.Printf("Open or Create file %s failed, %s", filename, err.Error())
 		return
 	}
-	p.fd = fd
-	p.filename = filename
+	p.exporter = exporter
+}
+
+// Export makes the result of the next statement exported by the exporter
+func (p *DataSetPrinter) Export(exporter ResultExporter) {
+	p.resultExporter = exporter
+}
+
+// plainString returns the value written in the exported file, strings are not quoted
+// since the file format handles the quoting
+func plainString(val *nebula.ValueWrapper) string {
+	if val.IsString() {
+		if s, err := val.AsString(); err == nil {
+			return s
+		}
+	}
+	return val.String()
 }
 
 func configWriterTckStyle(writer *table.Writer) {
@@ -66,39 +88,74 @@ func (p *DataSetPrinter) PrintDataSet(res *nebula.ResultSet) {
 		configWriterTckStyle(&p.writer)
 	}
 
+	exporter := p.exporter
+	p.exporter = nil
+	echo := exporter == nil || exporter.Echo()
+
 	var header []interface{}
 	for _, columName := range res.GetColNames() {
 		header = append(header, string(columName))
 	}
-	p.writer.AppendHeader(table.Row(header))
+	if echo {
+		p.writer.AppendHeader(table.Row(header))
+	}
+	if exporter != nil {
+		if err := exporter.WriteHeader(res.GetColNames()); err != nil {
+			fmt.Printf("Write file %s failed, %s", exporter.Filename(), err.Error())
+		}
+	}
 	numRows := res.GetRowSize()
 	numCols := res.GetColSize()
 	for i := 0; i < numRows; i++ {
 		var newRow []interface{}
-		record, err := res.GetRowValuesByIndex(i)
+		var record []string
+		values, err := res.GetRowValuesByIndex(i)
 		if err != nil {
 			continue
 		}
 		for j := 0; j < numCols; j++ {
-			val, err := record.GetValueByIndex(j)
-			if err != nil {
-				continue
+			// A cell which can not be read is empty, so the columns after it are aligned
+			text, plain := "", ""
+			if val, err := values.GetValueByIndex(j); err == nil {
+				text, plain = val.String(), plainString(val)
+			}
+			if echo {
+				newRow = append(newRow, text)
+			}
+			if exporter != nil {
+				record = append(record, plain)
+			}
+		}
+		if echo {
+			p.writer.AppendRow(table.Row(newRow))
+		}
+		if exporter != nil {
+			if err := exporter.WriteRow(record); err != nil {
+				fmt.Printf("Write file %s failed, %s", exporter.Filename(), err.Error())
+				exporter.Close()
+				exporter = nil
 			}
-			newRow = append(newRow, val.String())
 		}
-		p.writer.AppendRow(table.Row(newRow))
 	}
 
-	fmt.Println(p.writer.Render())
-	if p.fd != nil {
-		s := strings.Replace(p.writer.RenderCSV(), "\\\"", "", -1)
-		fmt.Fprintln(p.fd, s)
+	if echo {
+		fmt.Println(p.writer.Render())
+	}
+	if exporter != nil {
+		if err := exporter.Close(); err != nil {
+			fmt.Println(err.Error())
+		} else if !echo {
+			fmt.Printf("Exported %d rows to %s\n", numRows, exporter.Filename())
+		}
+	}
 
-		if err := p.fd.Close(); err != nil {
-			fmt.Printf("Close file %s failed, %s", p.filename, err.Error())
+	if p.resultExporter != nil {
+		if err := p.resultExporter.Export(res); err != nil {
+			fmt.Printf("Export to file %s failed, %s\n", p.resultExporter.Filename(), err.Error())
+		} else {
+			fmt.Printf("Exported %d rows to %s\n", numRows, p.resultExporter.Filename())
 		}
-		p.fd = nil
-		p.filename = ""
+		p.resultExporter = nil
 	}
 
 	// Reset the writer style
@@ -0,0 +1,467 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package printer
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+)
+
+// graphNode is a distinct vertex collected from a result set
+type graphNode struct {
+	vid   nebula.ValueWrapper
+	tags  []string
+	props map[string]map[string]*nebula.ValueWrapper
+}
+
+// graphEdge is a distinct edge collected from a result set
+type graphEdge struct {
+	src, dst nebula.ValueWrapper
+	name     string
+	rank     int64
+	props    map[string]*nebula.ValueWrapper
+}
+
+// GraphExporter writes the vertices and edges of the result set of the next
+// statement to a GraphML, GEXF or nGQL file, the format depends on the file extension
+type GraphExporter struct {
+	filename string
+	format   string
+
+	nodes     []*graphNode
+	nodeIndex map[string]*graphNode
+	edges     []*graphEdge
+	edgeIndex map[string]*graphEdge
+}
+
+func NewGraphExporter(filename string) (*GraphExporter, error) {
+	format := strings.ToLower(strings.TrimPrefix(filepath.Ext(filename), "."))
+	switch format {
+	case "graphml", "gexf", "ngql":
+	default:
+		return nil, fmt.Errorf("unsupported graph file %s, the extension should be .graphml, .gexf or .ngql", filename)
+	}
+	return &GraphExporter{
+		filename: filename,
+		format:   format,
+	}, nil
+}
+
+func (e *GraphExporter) Filename() string {
+	return e.filename
+}
+
+func (e *GraphExporter) Export(res *nebula.ResultSet) error {
+	e.nodes, e.edges = nil, nil
+	e.nodeIndex = make(map[string]*graphNode)
+	e.edgeIndex = make(map[string]*graphEdge)
+	for i := 0; i < res.GetRowSize(); i++ {
+		record, err := res.GetRowValuesByIndex(i)
+		if err != nil {
+			return err
+		}
+		for j := 0; j < res.GetColSize(); j++ {
+			val, err := record.GetValueByIndex(j)
+			if err != nil {
+				return err
+			}
+			e.collect(*val)
+		}
+	}
+	if len(e.nodes) == 0 && len(e.edges) == 0 {
+		return fmt.Errorf("no vertex or edge found in the result")
+	}
+
+	fd, err := os.OpenFile(e.filename, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer fd.Close()
+	w := bufio.NewWriter(fd)
+	switch e.format {
+	case "graphml":
+		e.writeGraphML(w)
+	case "gexf":
+		e.writeGEXF(w)
+	case "ngql":
+		e.writeNGQL(w)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	return fd.Close()
+}
+
+// collect walks through the value, the vertices and edges nested in paths,
+// lists, sets and maps are collected too
+func (e *GraphExporter) collect(v nebula.ValueWrapper) {
+	switch {
+	case v.IsVertex():
+		if node, err := v.AsNode(); err == nil {
+			e.addNode(node)
+		}
+	case v.IsEdge():
+		if rel, err := v.AsRelationship(); err == nil {
+			e.addEdge(rel)
+		}
+	case v.IsPath():
+		if path, err := v.AsPath(); err == nil {
+			for _, node := range path.GetNodes() {
+				e.addNode(node)
+			}
+			for _, rel := range path.GetRelationships() {
+				e.addEdge(rel)
+			}
+		}
+	case v.IsList(), v.IsSet():
+		for _, elem := range listElements(v) {
+			e.collect(elem)
+		}
+	case v.IsMap():
+		if m, err := v.AsMap(); err == nil {
+			for _, item := range m {
+				e.collect(item)
+			}
+		}
+	}
+}
+
+// addNode merges the tags of the vertices with the same vid
+func (e *GraphExporter) addNode(node *nebula.Node) {
+	key := node.GetID().String()
+	n, ok := e.nodeIndex[key]
+	if !ok {
+		n = &graphNode{
+			vid:   node.GetID(),
+			props: make(map[string]map[string]*nebula.ValueWrapper),
+		}
+		e.nodeIndex[key] = n
+		e.nodes = append(e.nodes, n)
+	}
+	for _, tag := range node.GetTags() {
+		if _, ok := n.props[tag]; ok {
+			continue
+		}
+		props, err := node.Properties(tag)
+		if err != nil {
+			continue
+		}
+		n.tags = append(n.tags, tag)
+		n.props[tag] = props
+	}
+}
+
+func (e *GraphExporter) addEdge(rel *nebula.Relationship) {
+	src, dst := rel.GetSrcVertexID(), rel.GetDstVertexID()
+	key := fmt.Sprintf("%s->%s@%d:%s", src.String(), dst.String(), rel.GetRanking(), rel.GetEdgeName())
+	if _, ok := e.edgeIndex[key]; ok {
+		return
+	}
+	edge := &graphEdge{
+		src:   src,
+		dst:   dst,
+		name:  rel.GetEdgeName(),
+		rank:  rel.GetRanking(),
+		props: rel.Properties(),
+	}
+	e.edgeIndex[key] = edge
+	e.edges = append(e.edges, edge)
+}
+
+func sortedKeys(props map[string]*nebula.ValueWrapper) []string {
+	keys := make([]string, 0, len(props))
+	for k := range props {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func xmlEscape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// graphAttr is a property declared in the header of GraphML and GEXF
+type graphAttr struct {
+	id   string
+	name string
+	typ  string
+}
+
+// attrType returns the GraphML type of the value, GEXF uses the same names
+func attrType(v *nebula.ValueWrapper) string {
+	switch {
+	case v.IsBool():
+		return "boolean"
+	case v.IsInt():
+		return "long"
+	case v.IsFloat():
+		return "double"
+	default:
+		return "string"
+	}
+}
+
+// collectAttrs declares the node properties as "tag.prop" and the edge properties as "edge.prop",
+// a property having values of different types is declared as string
+func (e *GraphExporter) collectAttrs() (nodeAttrs, edgeAttrs []graphAttr, nodeAttrIds, edgeAttrIds map[string]string) {
+	declare := func(attrs []graphAttr, ids map[string]string, prefix, name string, v *nebula.ValueWrapper) []graphAttr {
+		// null values are not written, they don't decide the type
+		if v.IsNull() || v.IsEmpty() {
+			return attrs
+		}
+		if id, ok := ids[name]; ok {
+			for i := range attrs {
+				if attrs[i].id == id && attrs[i].typ != attrType(v) {
+					attrs[i].typ = "string"
+				}
+			}
+			return attrs
+		}
+		id := fmt.Sprintf("%s%d", prefix, len(attrs))
+		ids[name] = id
+		return append(attrs, graphAttr{id: id, name: name, typ: attrType(v)})
+	}
+	nodeAttrIds = make(map[string]string)
+	edgeAttrIds = make(map[string]string)
+	for _, n := range e.nodes {
+		for _, tag := range n.tags {
+			for _, k := range sortedKeys(n.props[tag]) {
+				nodeAttrs = declare(nodeAttrs, nodeAttrIds, "v", tag+"."+k, n.props[tag][k])
+			}
+		}
+	}
+	for _, edge := range e.edges {
+		for _, k := range sortedKeys(edge.props) {
+			edgeAttrs = declare(edgeAttrs, edgeAttrIds, "e", edge.name+"."+k, edge.props[k])
+		}
+	}
+	return
+}
+
+func (e *GraphExporter) writeGraphML(w *bufio.Writer) {
+	nodeAttrs, edgeAttrs, nodeAttrIds, edgeAttrIds := e.collectAttrs()
+	fmt.Fprintln(w, `<?xml version="1.0" encoding="UTF-8"?>`)
+	fmt.Fprintln(w, `<graphml xmlns="http://graphml.graphdrawing.org/xmlns">`)
+	fmt.Fprintln(w, `  <key id="labels" for="node" attr.name="labels" attr.type="string"/>`)
+	fmt.Fprintln(w, `  <key id="label" for="edge" attr.name="label" attr.type="string"/>`)
+	fmt.Fprintln(w, `  <key id="rank" for="edge" attr.name="rank" attr.type="long"/>`)
+	for _, a := range nodeAttrs {
+		fmt.Fprintf(w, "  <key id=\"%s\" for=\"node\" attr.name=\"%s\" attr.type=\"%s\"/>\n", a.id, xmlEscape(a.name), a.typ)
+	}
+	for _, a := range edgeAttrs {
+		fmt.Fprintf(w, "  <key id=\"%s\" for=\"edge\" attr.name=\"%s\" attr.type=\"%s\"/>\n", a.id, xmlEscape(a.name), a.typ)
+	}
+	fmt.Fprintln(w, `  <graph id="G" edgedefault="directed">`)
+	for _, n := range e.nodes {
+		fmt.Fprintf(w, "    <node id=\"%s\">\n", xmlEscape(plainString(&n.vid)))
+		fmt.Fprintf(w, "      <data key=\"labels\">%s</data>\n", xmlEscape(strings.Join(n.tags, ":")))
+		for _, tag := range n.tags {
+			for _, k := range sortedKeys(n.props[tag]) {
+				v := n.props[tag][k]
+				if v.IsNull() || v.IsEmpty() {
+					continue
+				}
+				fmt.Fprintf(w, "      <data key=\"%s\">%s</data>\n", nodeAttrIds[tag+"."+k], xmlEscape(plainString(v)))
+			}
+		}
+		fmt.Fprintln(w, "    </node>")
+	}
+	for _, edge := range e.edges {
+		fmt.Fprintf(w, "    <edge source=\"%s\" target=\"%s\">\n", xmlEscape(plainString(&edge.src)), xmlEscape(plainString(&edge.dst)))
+		fmt.Fprintf(w, "      <data key=\"label\">%s</data>\n", xmlEscape(edge.name))
+		fmt.Fprintf(w, "      <data key=\"rank\">%d</data>\n", edge.rank)
+		for _, k := range sortedKeys(edge.props) {
+			v := edge.props[k]
+			if v.IsNull() || v.IsEmpty() {
+				continue
+			}
+			fmt.Fprintf(w, "      <data key=\"%s\">%s</data>\n", edgeAttrIds[edge.name+"."+k], xmlEscape(plainString(v)))
+		}
+		fmt.Fprintln(w, "    </edge>")
+	}
+	fmt.Fprintln(w, "  </graph>")
+	fmt.Fprintln(w, "</graphml>")
+}
+
+func (e *GraphExporter) writeGEXF(w *bufio.Writer) {
+	nodeAttrs, edgeAttrs, nodeAttrIds, edgeAttrIds := e.collectAttrs()
+	fmt.Fprintln(w, `<?xml version="1.0" encoding="UTF-8"?>`)
+	fmt.Fprintln(w, `<gexf xmlns="http://gexf.net/1.2" version="1.2">`)
+	fmt.Fprintln(w, `  <graph mode="static" defaultedgetype="directed">`)
+	fmt.Fprintln(w, `    <attributes class="node">`)
+	for _, a := range nodeAttrs {
+		fmt.Fprintf(w, "      <attribute id=\"%s\" title=\"%s\" type=\"%s\"/>\n", a.id, xmlEscape(a.name), a.typ)
+	}
+	fmt.Fprintln(w, `    </attributes>`)
+	fmt.Fprintln(w, `    <attributes class="edge">`)
+	fmt.Fprintln(w, `      <attribute id="rank" title="rank" type="long"/>`)
+	for _, a := range edgeAttrs {
+		fmt.Fprintf(w, "      <attribute id=\"%s\" title=\"%s\" type=\"%s\"/>\n", a.id, xmlEscape(a.name), a.typ)
+	}
+	fmt.Fprintln(w, `    </attributes>`)
+	fmt.Fprintln(w, `    <nodes>`)
+	for _, n := range e.nodes {
+		vid := xmlEscape(plainString(&n.vid))
+		fmt.Fprintf(w, "      <node id=\"%s\" label=\"%s\">\n", vid, vid)
+		fmt.Fprintln(w, "        <attvalues>")
+		for _, tag := range n.tags {
+			for _, k := range sortedKeys(n.props[tag]) {
+				v := n.props[tag][k]
+				if v.IsNull() || v.IsEmpty() {
+					continue
+				}
+				fmt.Fprintf(w, "          <attvalue for=\"%s\" value=\"%s\"/>\n", nodeAttrIds[tag+"."+k], xmlEscape(plainString(v)))
+			}
+		}
+		fmt.Fprintln(w, "        </attvalues>")
+		fmt.Fprintln(w, "      </node>")
+	}
+	fmt.Fprintln(w, `    </nodes>`)
+	fmt.Fprintln(w, `    <edges>`)
+	for i, edge := range e.edges {
+		fmt.Fprintf(w, "      <edge id=\"%d\" source=\"%s\" target=\"%s\" label=\"%s\">\n", i,
+			xmlEscape(plainString(&edge.src)), xmlEscape(plainString(&edge.dst)), xmlEscape(edge.name))
+		fmt.Fprintln(w, "        <attvalues>")
+		fmt.Fprintf(w, "          <attvalue for=\"rank\" value=\"%d\"/>\n", edge.rank)
+		for _, k := range sortedKeys(edge.props) {
+			v := edge.props[k]
+			if v.IsNull() || v.IsEmpty() {
+				continue
+			}
+			fmt.Fprintf(w, "          <attvalue for=\"%s\" value=\"%s\"/>\n", edgeAttrIds[edge.name+"."+k], xmlEscape(plainString(v)))
+		}
+		fmt.Fprintln(w, "        </attvalues>")
+		fmt.Fprintln(w, "      </edge>")
+	}
+	fmt.Fprintln(w, `    </edges>`)
+	fmt.Fprintln(w, `  </graph>`)
+	fmt.Fprintln(w, `</gexf>`)
+}
+
+var ngqlStringEscaper = strings.NewReplacer(
+	"\\", "\\\\",
+	"\"", "\\\"",
+	"\n", "\\n",
+	"\r", "\\r",
+	"\t", "\\t",
+)
+
+// QuoteNGQLString returns the string as a double-quoted nGQL string literal
+func QuoteNGQLString(s string) string {
+	return "\"" + ngqlStringEscaper.Replace(s) + "\""
+}
+
+// NGQLLiteral returns the value as a nGQL literal which can be used in INSERT statements
+func NGQLLiteral(v *nebula.ValueWrapper) string {
+	switch {
+	case v.IsNull() || v.IsEmpty():
+		return "NULL"
+	case v.IsString():
+		s, _ := v.AsString()
+		return QuoteNGQLString(s)
+	case v.IsDate():
+		return fmt.Sprintf("date(\"%s\")", v.String())
+	case v.IsTime():
+		return fmt.Sprintf("time(\"%s\")", v.String())
+	case v.IsDateTime():
+		return fmt.Sprintf("datetime(\"%s\")", v.String())
+	case v.IsDuration():
+		d, _ := v.AsDuration()
+		return fmt.Sprintf("duration({months: %d, seconds: %d, microseconds: %d})", d.GetMonths(), d.GetSeconds(), d.GetMicroseconds())
+	case v.IsGeography():
+		return fmt.Sprintf("ST_GeogFromText(\"%s\")", v.String())
+	case v.IsList(), v.IsSet():
+		var elems []string
+		for _, elem := range listElements(*v) {
+			elems = append(elems, NGQLLiteral(&elem))
+		}
+		if v.IsSet() {
+			return fmt.Sprintf("set{%s}", strings.Join(elems, ", "))
+		}
+		return fmt.Sprintf("[%s]", strings.Join(elems, ", "))
+	case v.IsMap():
+		m, _ := v.AsMap()
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		var items []string
+		for _, k := range keys {
+			item := m[k]
+			items = append(items, fmt.Sprintf("`%s`: %s", k, NGQLLiteral(&item)))
+		}
+		return fmt.Sprintf("{%s}", strings.Join(items, ", "))
+	case v.IsFloat():
+		f, _ := v.AsFloat()
+		return floatLiteral(f)
+	default:
+		// bool and int
+		return v.String()
+	}
+}
+
+// floatLiteral formats the float without the exponent, which nGQL doesn't parse after the ".0"
+// appended by ValueWrapper.String(), i.e. 1e+06.0. nGQL has no literals of NaN and infinities,
+// they are converted from strings.
+func floatLiteral(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return `toFloat("NaN")`
+	case math.IsInf(f, 1):
+		return `toFloat("Infinity")`
+	case math.IsInf(f, -1):
+		return `toFloat("-Infinity")`
+	}
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	if !strings.Contains(s, ".") {
+		s += ".0"
+	}
+	return s
+}
+
+func (e *GraphExporter) writeNGQL(w *bufio.Writer) {
+	for _, n := range e.nodes {
+		vid := NGQLLiteral(&n.vid)
+		if len(n.tags) == 0 {
+			fmt.Fprintf(w, "-- vertex %s has no tag\n", vid)
+			continue
+		}
+		var tagSchemas, tagValues []string
+		for _, tag := range n.tags {
+			var names, values []string
+			for _, k := range sortedKeys(n.props[tag]) {
+				names = append(names, fmt.Sprintf("`%s`", k))
+				values = append(values, NGQLLiteral(n.props[tag][k]))
+			}
+			tagSchemas = append(tagSchemas, fmt.Sprintf("`%s`(%s)", tag, strings.Join(names, ", ")))
+			tagValues = append(tagValues, values...)
+		}
+		fmt.Fprintf(w, "INSERT VERTEX IF NOT EXISTS %s VALUES %s:(%s);\n",
+			strings.Join(tagSchemas, ", "), vid, strings.Join(tagValues, ", "))
+	}
+	for _, edge := range e.edges {
+		var names, values []string
+		for _, k := range sortedKeys(edge.props) {
+			names = append(names, fmt.Sprintf("`%s`", k))
+			values = append(values, NGQLLiteral(edge.props[k]))
+		}
+		fmt.Fprintf(w, "INSERT EDGE IF NOT EXISTS `%s`(%s) VALUES %s->%s@%d:(%s);\n",
+			edge.name, strings.Join(names, ", "), NGQLLiteral(&edge.src), NGQLLiteral(&edge.dst), edge.rank, strings.Join(values, ", "))
+	}
+}
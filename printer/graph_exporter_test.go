@@ -0,0 +1,121 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package printer
+
+import (
+	"encoding/xml"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	ttypes "github.com/vesoft-inc/nebula-go/v3/nebula"
+)
+
+// graphResult has the vertices and the edges in the columns, the paths and the lists,
+// in which the same vertices and edges appear more than once
+func graphResult(t *testing.T) *nebula.ResultSet {
+	tim := vertex("player100", "player", map[string]*ttypes.Value{"name": strValue(`Tim "The Big" Duncan`), "age": intValue(42)})
+	tony := vertex("player101", "player", map[string]*ttypes.Value{"name": strValue("Tony Parker"), "age": nullValue()})
+	follow := edge("player100", "player101", "follow", 0, map[string]*ttypes.Value{"degree": floatValue(95)})
+	path := &ttypes.Path{Src: tim, Steps: []*ttypes.Step{{Dst: tony, Type: 1, Name: []byte("follow"), Props: follow.Props}}}
+	return resultSet(t, []string{"v", "e", "p", "l"},
+		[]*ttypes.Value{{VVal: tim}, {EVal: follow}, {PVal: path}, listValue(&ttypes.Value{VVal: tony})},
+		[]*ttypes.Value{{VVal: tony}, nullValue(), nullValue(), listValue()},
+	)
+}
+
+func export(t *testing.T, filename string, res *nebula.ResultSet) string {
+	path := filepath.Join(t.TempDir(), filename)
+	e, err := NewGraphExporter(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Export(res); err != nil {
+		t.Fatal(err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(content)
+}
+
+func TestGraphExporterNGQL(t *testing.T) {
+	got := export(t, "graph.ngql", graphResult(t))
+	want := "INSERT VERTEX IF NOT EXISTS `player`(`age`, `name`) VALUES \"player100\":(42, \"Tim \\\"The Big\\\" Duncan\");\n" +
+		"INSERT VERTEX IF NOT EXISTS `player`(`age`, `name`) VALUES \"player101\":(NULL, \"Tony Parker\");\n" +
+		"INSERT EDGE IF NOT EXISTS `follow`(`degree`) VALUES \"player100\"->\"player101\"@0:(95.0);\n"
+	if got != want {
+		t.Errorf("exported nGQL =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGraphExporterXML(t *testing.T) {
+	for _, filename := range []string{"graph.graphml", "graph.gexf"} {
+		got := export(t, filename, graphResult(t))
+		// The file is well-formed, the quotes of the properties are escaped
+		decoder := xml.NewDecoder(strings.NewReader(got))
+		for {
+			_, err := decoder.Token()
+			if err != nil {
+				if err != io.EOF {
+					t.Errorf("%s is not well-formed, %s", filename, err)
+				}
+				break
+			}
+		}
+		// The vertices and the edges are deduplicated
+		if n := strings.Count(got, "<node "); n != 2 {
+			t.Errorf("%s has %d vertices, want 2", filename, n)
+		}
+		if n := strings.Count(got, "<edge "); n != 1 {
+			t.Errorf("%s has %d edges, want 1", filename, n)
+		}
+		if !strings.Contains(got, "Tim &#34;The Big&#34; Duncan") {
+			t.Errorf("%s doesn't have the escaped name:\n%s", filename, got)
+		}
+	}
+}
+
+func TestGraphExporterErrors(t *testing.T) {
+	if _, err := NewGraphExporter("graph.json"); err == nil {
+		t.Errorf("NewGraphExporter(graph.json) succeeded, want an error")
+	}
+	e, err := NewGraphExporter(filepath.Join(t.TempDir(), "graph.ngql"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := e.Export(resultSet(t, []string{"n"}, []*ttypes.Value{intValue(1)})); err == nil {
+		t.Errorf("Export without any vertex or edge succeeded, want an error")
+	}
+}
+
+func TestNGQLLiteral(t *testing.T) {
+	cases := []struct {
+		value *ttypes.Value
+		want  string
+	}{
+		{nullValue(), "NULL"},
+		{strValue("a\"b\\c\n"), `"a\"b\\c\n"`},
+		{intValue(-3), "-3"},
+		{floatValue(1e6), "1000000.0"},
+		{floatValue(0.25), "0.25"},
+		{listValue(intValue(1), strValue("x")), `[1, "x"]`},
+		{&ttypes.Value{MVal: &ttypes.NMap{Kvs: map[string]*ttypes.Value{"b": intValue(2), "a": intValue(1)}}}, "{`a`: 1, `b`: 2}"},
+	}
+	for _, c := range cases {
+		column, err := resultSet(t, []string{"c"}, []*ttypes.Value{c.value}).GetValuesByColName("c")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := NGQLLiteral(column[0]); got != c.want {
+			t.Errorf("NGQLLiteral(%v) = %s, want %s", column[0], got, c.want)
+		}
+	}
+}
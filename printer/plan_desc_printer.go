@@ -25,9 +25,10 @@ func graphvizString(s string) string {
 }
 
 type PlanDescPrinter struct {
-	writer   table.Writer
-	fd       *os.File
-	filename string
+	writer     table.Writer
+	fd         *os.File
+	filename   string
+	aggregator *ProfileAggregator
 }
 
 func NewPlanDescPrinter() PlanDescPrinter {
@@ -167,3 +168,61 @@ func (p *PlanDescPrinter) PrintPlanDesc(res *nebula.ResultSet) {
 		}()
 	}
 }
+
+// StartAggregation makes the following plan descriptions merged instead of printed,
+// until PrintAggregation is called
+func (p *PlanDescPrinter) StartAggregation() {
+	p.aggregator = newProfileAggregator()
+}
+
+func (p *PlanDescPrinter) StopAggregation() {
+	p.aggregator = nil
+}
+
+func (p *PlanDescPrinter) IsAggregating() bool {
+	return p.aggregator != nil
+}
+
+func (p *PlanDescPrinter) AddToAggregation(res *nebula.ResultSet) {
+	if p.aggregator == nil {
+		return
+	}
+	p.aggregator.Add(res)
+}
+
+// PrintAggregation prints the consolidated plan in the row or tck format chosen by the statement,
+// the profiling data is shown as min/median/max. The dot formats can't show the merged data.
+func (p *PlanDescPrinter) PrintAggregation() error {
+	if p.aggregator == nil {
+		return nil
+	}
+	if p.aggregator.Runs() == 0 {
+		return nil
+	}
+	var s string
+	switch format := p.aggregator.Format(); format {
+	case "row", "":
+		s = p.renderByRow(p.aggregator.MakePlanByRow())
+	case "tck":
+		s = p.renderByTck(p.aggregator.MakePlanByTck())
+		// Reset the writer style
+		p.writer.SetStyle(table.StyleDefault)
+		configTableWriter(&p.writer, true)
+	default:
+		return fmt.Errorf("the profiling data merged from %d runs can't be printed in the %s format, use row or tck", p.aggregator.Runs(), format)
+	}
+	fmt.Printf("Execution Plan (profiling data merged from %d runs, min/median/max)\n", p.aggregator.Runs())
+	fmt.Println()
+	fmt.Println(s)
+	fmt.Println()
+
+	if p.fd != nil {
+		fmt.Fprintln(p.fd, s)
+		if err := p.fd.Close(); err != nil {
+			fmt.Printf("Close file %s failed, %s", p.filename, err.Error())
+		}
+		p.fd = nil
+		p.filename = ""
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package printer
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+)
+
+// profileSamples keeps the profiling data of one plan node, one sample per run
+type profileSamples struct {
+	rows      []int64
+	execTime  []int64
+	totalTime []int64
+}
+
+// ProfileAggregator merges the profiling data of the same statement executed
+// several times by `:repeat`, so that only one plan is printed at the end
+type ProfileAggregator struct {
+	nodes   []*graph.PlanNodeDescription
+	samples map[int64]*profileSamples
+	runs    int
+	// format is the plan format of the first run, i.e. row, tck or dot
+	format string
+}
+
+func newProfileAggregator() *ProfileAggregator {
+	return &ProfileAggregator{
+		samples: make(map[int64]*profileSamples),
+	}
+}
+
+// Add merges the plan description of one run.
+// A plan node executed in a loop has several profiles, they are summed up as one sample.
+func (a *ProfileAggregator) Add(res *nebula.ResultSet) {
+	if !res.IsSetPlanDesc() {
+		return
+	}
+	planNodeDescs := res.GetPlanDesc().GetPlanNodeDescs()
+	if a.nodes == nil {
+		a.nodes = planNodeDescs
+		a.format = strings.ToLower(string(res.GetPlanDesc().GetFormat()))
+	}
+	for _, planNodeDesc := range planNodeDescs {
+		if !planNodeDesc.IsSetProfiles() {
+			continue
+		}
+		var rows, execTime, totalTime int64
+		for _, profile := range planNodeDesc.GetProfiles() {
+			rows += profile.GetRows()
+			execTime += profile.GetExecDurationInUs()
+			totalTime += profile.GetTotalDurationInUs()
+		}
+		s, ok := a.samples[planNodeDesc.GetId()]
+		if !ok {
+			s = &profileSamples{}
+			a.samples[planNodeDesc.GetId()] = s
+		}
+		s.rows = append(s.rows, rows)
+		s.execTime = append(s.execTime, execTime)
+		s.totalTime = append(s.totalTime, totalTime)
+	}
+	a.runs++
+}
+
+// Format returns the plan format chosen by the statement
+func (a *ProfileAggregator) Format() string {
+	return a.format
+}
+
+// Runs returns the number of merged plan descriptions
+func (a *ProfileAggregator) Runs() int {
+	return a.runs
+}
+
+// minMedianMax returns the min, median and max of the samples
+func minMedianMax(samples []int64) (int64, int64, int64) {
+	sorted := make([]int64, len(samples))
+	copy(sorted, samples)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	n := len(sorted)
+	median := sorted[n/2]
+	if n%2 == 0 {
+		median = (sorted[n/2-1] + sorted[n/2]) / 2
+	}
+	return sorted[0], median, sorted[n-1]
+}
+
+func (a *ProfileAggregator) makeProfilingData(id int64) string {
+	s, ok := a.samples[id]
+	if !ok || len(s.rows) == 0 {
+		return ""
+	}
+	var stats []string
+	stats = append(stats, fmt.Sprintf("runs: %d", len(s.rows)))
+	min, median, max := minMedianMax(s.rows)
+	stats = append(stats, fmt.Sprintf("rows: %d/%d/%d", min, median, max))
+	min, median, max = minMedianMax(s.execTime)
+	stats = append(stats, fmt.Sprintf("execTime: %d/%d/%d(us)", min, median, max))
+	min, median, max = minMedianMax(s.totalTime)
+	stats = append(stats, fmt.Sprintf("totalTime: %d/%d/%d(us)", min, median, max))
+	return strings.Join(stats, "\n")
+}
+
+// MakePlanByRow generates the rows of the consolidated plan,
+// the profiling data column is formatted as min/median/max
+func (a *ProfileAggregator) MakePlanByRow() [][]interface{} {
+	var rows [][]interface{}
+	for _, planNodeDesc := range a.nodes {
+		var row []interface{}
+		row = append(row, planNodeDesc.GetId(), string(planNodeDesc.GetName()))
+
+		if planNodeDesc.IsSetDependencies() {
+			var deps []string
+			for _, dep := range planNodeDesc.GetDependencies() {
+				deps = append(deps, fmt.Sprintf("%d", dep))
+			}
+			row = append(row, strings.Join(deps, ","))
+		} else {
+			row = append(row, "")
+		}
+
+		row = append(row, a.makeProfilingData(planNodeDesc.GetId()))
+		row = append(row, nebula.MakeOperatorInfo(planNodeDesc))
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// tckStats is the min/median/max of a profiling metric in the tck format
+type tckStats struct {
+	Min    int64 `json:"min"`
+	Median int64 `json:"median"`
+	Max    int64 `json:"max"`
+}
+
+func newTckStats(samples []int64) tckStats {
+	min, median, max := minMedianMax(samples)
+	return tckStats{Min: min, Median: median, Max: max}
+}
+
+// MakePlanByTck generates the rows of the consolidated plan in the tck format,
+// the profiling data column is the compact JSON of min/median/max
+func (a *ProfileAggregator) MakePlanByTck() [][]interface{} {
+	rows := a.MakePlanByRow()
+	for i, planNodeDesc := range a.nodes {
+		rows[i][3], rows[i][4] = "", ""
+		s, ok := a.samples[planNodeDesc.GetId()]
+		if !ok || len(s.rows) == 0 {
+			continue
+		}
+		data, _ := json.Marshal(struct {
+			Runs      int      `json:"runs"`
+			Rows      tckStats `json:"rows"`
+			ExecTime  tckStats `json:"execTime"`
+			TotalTime tckStats `json:"totalTime"`
+		}{len(s.rows), newTckStats(s.rows), newTckStats(s.execTime), newTckStats(s.totalTime)})
+		rows[i][3] = string(data)
+	}
+	return rows
+}
@@ -0,0 +1,80 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package printer
+
+import (
+	"reflect"
+	"testing"
+
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+)
+
+// profile is the result of a PROFILE run, the profiles of the Start node are the rows of each loop
+func profile(t *testing.T, format string, loopRows ...int64) *nebula.ResultSet {
+	start := &graph.PlanNodeDescription{Name: []byte("Start"), Id: 0}
+	for _, rows := range loopRows {
+		start.Profiles = append(start.Profiles, &graph.ProfilingStats{Rows: rows, ExecDurationInUs: rows * 10, TotalDurationInUs: rows * 20})
+	}
+	project := &graph.PlanNodeDescription{Name: []byte("Project"), Id: 1, Dependencies: []int64{0},
+		Profiles: []*graph.ProfilingStats{{Rows: 1, ExecDurationInUs: 5, TotalDurationInUs: 6}}}
+	res, err := nebula.GenResultSet(&graph.ExecutionResponse{PlanDesc: &graph.PlanDescription{
+		PlanNodeDescs: []*graph.PlanNodeDescription{start, project},
+		Format:        []byte(format),
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return res
+}
+
+func TestProfileAggregator(t *testing.T) {
+	a := newProfileAggregator()
+	// The loops of a node are summed up as one sample
+	a.Add(profile(t, "row", 10))
+	a.Add(profile(t, "row", 20, 20))
+	a.Add(profile(t, "row", 30))
+	a.Add(profile(t, "row", 5, 10))
+	if a.Runs() != 4 || a.Format() != "row" {
+		t.Errorf("Runs = %d, Format = %q, want 4 runs of row", a.Runs(), a.Format())
+	}
+
+	rows := a.MakePlanByRow()
+	want := "runs: 4\nrows: 10/22/40\nexecTime: 100/225/400(us)\ntotalTime: 200/450/800(us)"
+	if len(rows) != 2 || rows[0][1] != "Start" || rows[0][3] != want {
+		t.Errorf("MakePlanByRow = %q, want the Start node profiled as %q", rows, want)
+	}
+	if rows[1][2] != "0" || rows[1][3] != "runs: 4\nrows: 1/1/1\nexecTime: 5/5/5(us)\ntotalTime: 6/6/6(us)" {
+		t.Errorf("MakePlanByRow = %q, want the Project node depending on 0", rows[1])
+	}
+
+	tck := a.MakePlanByTck()
+	wantTck := []interface{}{int64(0), "Start", "",
+		`{"runs":4,"rows":{"min":10,"median":22,"max":40},"execTime":{"min":100,"median":225,"max":400},"totalTime":{"min":200,"median":450,"max":800}}`, ""}
+	if !reflect.DeepEqual(tck[0], wantTck) {
+		t.Errorf("MakePlanByTck = %q, want %q", tck[0], wantTck)
+	}
+}
+
+func TestPrintAggregationFormat(t *testing.T) {
+	cases := []struct {
+		format  string
+		wantErr bool
+	}{
+		{"row", false},
+		{"tck", false},
+		{"dot", true},
+		{"dot:struct", true},
+	}
+	for _, c := range cases {
+		p := NewPlanDescPrinter()
+		p.StartAggregation()
+		p.AddToAggregation(profile(t, c.format, 1))
+		if err := p.PrintAggregation(); (err != nil) != c.wantErr {
+			t.Errorf("PrintAggregation in %s = %v, want error %t", c.format, err, c.wantErr)
+		}
+	}
+}
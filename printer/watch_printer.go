@@ -0,0 +1,175 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package printer
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+)
+
+// WatchPrinter renders the result set of a statement executed repeatedly,
+// the cells changed since the previous run are highlighted
+type WatchPrinter struct {
+	writer table.Writer
+	// the cells of the previous run by row and column
+	previous [][]string
+}
+
+var changedColors = text.Colors{text.BgYellow, text.FgBlack}
+
+func NewWatchPrinter() *WatchPrinter {
+	writer := table.NewWriter()
+	configTableWriter(&writer, false)
+	return &WatchPrinter{writer: writer}
+}
+
+// Render returns the table of the result set
+func (p *WatchPrinter) Render(res *nebula.ResultSet) string {
+	if res.GetColSize() == 0 {
+		return ""
+	}
+	p.writer.ResetHeaders()
+	p.writer.ResetRows()
+
+	var header []interface{}
+	for _, columnName := range res.GetColNames() {
+		header = append(header, columnName)
+	}
+	p.writer.AppendHeader(table.Row(header))
+
+	// The first run has nothing to compare with
+	first := p.previous == nil
+	current := make([][]string, 0, res.GetRowSize())
+	for i := 0; i < res.GetRowSize(); i++ {
+		values, err := res.GetRowValuesByIndex(i)
+		if err != nil {
+			continue
+		}
+		var row []interface{}
+		var cells []string
+		for j := 0; j < res.GetColSize(); j++ {
+			val, err := values.GetValueByIndex(j)
+			if err != nil {
+				continue
+			}
+			cell := val.String()
+			cells = append(cells, cell)
+			if !first && (i >= len(p.previous) || j >= len(p.previous[i]) || p.previous[i][j] != cell) {
+				row = append(row, changedColors.Sprint(cell))
+			} else {
+				row = append(row, cell)
+			}
+		}
+		current = append(current, cells)
+		p.writer.AppendRow(table.Row(row))
+	}
+	p.previous = current
+	return p.writer.Render()
+}
+
+// condition compares a column with a value, i.e. `Status == "FINISHED"`
+type condition struct {
+	column string
+	op     string
+	value  string
+}
+
+// Condition is the conditions joined by AND, which are met when every row satisfies them
+type Condition struct {
+	text       string
+	conditions []condition
+}
+
+var (
+	conditionRegexp = regexp.MustCompile(`^\s*(` + "`[^`]+`" + `|[^\s=!<>]+)\s*(==|!=|>=|<=|=|>|<)\s*(.+?)\s*$`)
+	andRegexp       = regexp.MustCompile(`(?i)\s+and\s+|&&`)
+)
+
+// ParseCondition parses the expression like `Status == FINISHED and Progress >= 100`,
+// the values may be quoted, and they are compared numerically if both sides are numbers
+func ParseCondition(expr string) (*Condition, error) {
+	c := &Condition{text: expr}
+	for _, item := range andRegexp.Split(expr, -1) {
+		m := conditionRegexp.FindStringSubmatch(item)
+		if m == nil {
+			return nil, fmt.Errorf("invalid condition %s, it should be like `column == value`", item)
+		}
+		op := m[2]
+		if op == "=" {
+			op = "=="
+		}
+		c.conditions = append(c.conditions, condition{
+			column: strings.Trim(m[1], "`"),
+			op:     op,
+			value:  strings.Trim(m[3], `"'`),
+		})
+	}
+	return c, nil
+}
+
+func (c *Condition) String() string {
+	return c.text
+}
+
+func (c condition) match(cell string) bool {
+	a, errA := strconv.ParseFloat(cell, 64)
+	b, errB := strconv.ParseFloat(c.value, 64)
+	cmp := 0
+	if errA == nil && errB == nil {
+		switch {
+		case a < b:
+			cmp = -1
+		case a > b:
+			cmp = 1
+		}
+	} else {
+		cmp = strings.Compare(cell, c.value)
+	}
+	switch c.op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	default:
+		return cmp <= 0
+	}
+}
+
+// Match checks whether every row of the result set satisfies the conditions,
+// an empty result set never matches
+func (c *Condition) Match(res *nebula.ResultSet) (bool, error) {
+	if res.GetRowSize() == 0 {
+		return false, nil
+	}
+	for i := 0; i < res.GetRowSize(); i++ {
+		record, err := res.GetRowValuesByIndex(i)
+		if err != nil {
+			return false, err
+		}
+		for _, cond := range c.conditions {
+			val, err := record.GetValueByColName(cond.column)
+			if err != nil {
+				return false, fmt.Errorf("column %s not found", cond.column)
+			}
+			if !cond.match(plainString(val)) {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
@@ -0,0 +1,91 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package printer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	ttypes "github.com/vesoft-inc/nebula-go/v3/nebula"
+)
+
+func TestParseCondition(t *testing.T) {
+	cases := []struct {
+		expr    string
+		want    []condition
+		wantErr bool
+	}{
+		{`Status == "FINISHED"`, []condition{{"Status", "==", "FINISHED"}}, false},
+		{"Status = FINISHED and Progress >= 100", []condition{{"Status", "==", "FINISHED"}, {"Progress", ">=", "100"}}, false},
+		{"`Job Id` != 'a b' && n<3", []condition{{"Job Id", "!=", "a b"}, {"n", "<", "3"}}, false},
+		{"n AND m > 1", nil, true},
+		{"Status", nil, true},
+	}
+	for _, c := range cases {
+		cond, err := ParseCondition(c.expr)
+		if (err != nil) != c.wantErr {
+			t.Errorf("ParseCondition(%q) error = %v, want error %t", c.expr, err, c.wantErr)
+			continue
+		}
+		if err == nil && (!reflect.DeepEqual(cond.conditions, c.want) || cond.String() != c.expr) {
+			t.Errorf("ParseCondition(%q) = %+v, want %+v", c.expr, cond.conditions, c.want)
+		}
+	}
+}
+
+func TestConditionMatch(t *testing.T) {
+	jobs := resultSet(t, []string{"Status", "Progress"},
+		[]*ttypes.Value{strValue("FINISHED"), intValue(100)},
+		[]*ttypes.Value{strValue("FINISHED"), floatValue(99.5)},
+	)
+	cases := []struct {
+		expr    string
+		want    bool
+		wantErr bool
+	}{
+		{"Status == FINISHED", true, false},
+		{"Status == FINISHED and Progress >= 100", false, false},
+		// The numbers are compared numerically, i.e. 99.5 < 100 while "99.5" > "100"
+		{"Progress > 99", true, false},
+		{"Progress <= 100", true, false},
+		{"Status != RUNNING", true, false},
+		{"Missing == 1", false, true},
+	}
+	for _, c := range cases {
+		cond, err := ParseCondition(c.expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := cond.Match(jobs)
+		if got != c.want || (err != nil) != c.wantErr {
+			t.Errorf("Match(%q) = %t, %v, want %t, error %t", c.expr, got, err, c.want, c.wantErr)
+		}
+	}
+	// An empty result never matches
+	cond, _ := ParseCondition("Status == FINISHED")
+	if got, _ := cond.Match(resultSet(t, []string{"Status"})); got {
+		t.Errorf("Match of an empty result = true, want false")
+	}
+}
+
+func TestWatchPrinterRender(t *testing.T) {
+	p := NewWatchPrinter()
+	first := p.Render(resultSet(t, []string{"n"}, []*ttypes.Value{intValue(1)}, []*ttypes.Value{intValue(2)}))
+	if strings.Contains(first, "\x1b[") {
+		t.Errorf("the first run is highlighted:\n%s", first)
+	}
+	// Only the changed cell and the new row are highlighted
+	second := p.Render(resultSet(t, []string{"n"}, []*ttypes.Value{intValue(1)}, []*ttypes.Value{intValue(3)}, []*ttypes.Value{intValue(4)}))
+	for _, cell := range []string{"3", "4"} {
+		if !strings.Contains(second, changedColors.Sprint(cell)) {
+			t.Errorf("the changed cell %s is not highlighted:\n%s", cell, second)
+		}
+	}
+	if strings.Contains(second, changedColors.Sprint("1")) {
+		t.Errorf("the unchanged cell 1 is highlighted:\n%s", second)
+	}
+}
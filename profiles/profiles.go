@@ -0,0 +1,36 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package profiles
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// Settings are the options of a connection profile, which are kept in a JSON file keyed by
+// the profile names, i.e. {"ci@192.168.8.1:9669": {"yes": true}}
+type Settings struct {
+	// Yes executes the destructive statements without confirmation like -yes
+	Yes bool `json:"yes"`
+}
+
+// Load returns the settings of the profile, the zero value is returned if the file or the profile
+// doesn't exist
+func Load(path, name string) (Settings, error) {
+	b, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return Settings{}, nil
+	}
+	if err != nil {
+		return Settings{}, err
+	}
+	var profiles map[string]Settings
+	if err := json.Unmarshal(b, &profiles); err != nil {
+		return Settings{}, fmt.Errorf("invalid profiles file %s, %s", path, err.Error())
+	}
+	return profiles[name], nil
+}
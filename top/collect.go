@@ -0,0 +1,278 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package top
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+)
+
+// Executor executes the statements of the dashboard, which is the session of the console,
+// or a mock server in tests
+type Executor interface {
+	Execute(stmt string) (*nebula.ResultSet, error)
+}
+
+// Host is a storaged host of SHOW HOSTS
+type Host struct {
+	Address               string
+	Status                string
+	Leaders               int64
+	LeaderDistribution    string
+	PartitionDistribution string
+	Version               string
+}
+
+// Service is a graphd, metad or storaged of SHOW HOSTS GRAPH/META/STORAGE
+type Service struct {
+	Role    string
+	Address string
+	Status  string
+	Version string
+}
+
+// Job is a job of the current space, the progress is the ratio of the finished tasks
+type Job struct {
+	ID        int64
+	Command   string
+	Status    string
+	StartTime string
+	Tasks     int
+	Finished  int
+}
+
+// Query is a running query of SHOW QUERIES
+type Query struct {
+	SessionID int64
+	PlanID    int64
+	User      string
+	Host      string
+	Duration  time.Duration
+	Status    string
+	Statement string
+}
+
+// Snapshot is the cluster status collected at a time, the statements that failed are
+// reported in Errors so that the others are still shown
+type Snapshot struct {
+	Time     time.Time
+	Space    string
+	Hosts    []Host
+	Services []Service
+	Jobs     []Job
+	Queries  []Query
+	Sessions int
+	Errors   []string
+}
+
+func execute(executor Executor, stmt string) (*nebula.ResultSet, error) {
+	res, err := executor.Execute(stmt)
+	if err != nil {
+		return nil, err
+	}
+	if !res.IsSucceed() {
+		return nil, fmt.Errorf("an error occurred when executing: %s, [ERROR (%d)]: %s", stmt, res.GetErrorCode(), res.GetErrorMsg())
+	}
+	return res, nil
+}
+
+func value(res *nebula.ResultSet, row int, col string) *nebula.ValueWrapper {
+	record, err := res.GetRowValuesByIndex(row)
+	if err != nil {
+		return nil
+	}
+	val, err := record.GetValueByColName(col)
+	if err != nil {
+		return nil
+	}
+	return val
+}
+
+func column(res *nebula.ResultSet, row int, col string) string {
+	val := value(res, row, col)
+	if val == nil {
+		return ""
+	}
+	if s, err := val.AsString(); err == nil {
+		return s
+	}
+	return val.String()
+}
+
+func intColumn(res *nebula.ResultSet, row int, col string) int64 {
+	val := value(res, row, col)
+	if val == nil {
+		return 0
+	}
+	i, _ := val.AsInt()
+	return i
+}
+
+func address(res *nebula.ResultSet, row int) string {
+	return fmt.Sprintf("%s:%d", column(res, row, "Host"), intColumn(res, row, "Port"))
+}
+
+func (s *Snapshot) collectHosts(executor Executor) {
+	res, err := execute(executor, "SHOW HOSTS")
+	if err != nil {
+		s.Errors = append(s.Errors, err.Error())
+		return
+	}
+	for i := 0; i < res.GetRowSize(); i++ {
+		s.Hosts = append(s.Hosts, Host{
+			Address:               address(res, i),
+			Status:                column(res, i, "Status"),
+			Leaders:               intColumn(res, i, "Leader count"),
+			LeaderDistribution:    column(res, i, "Leader distribution"),
+			PartitionDistribution: column(res, i, "Partition distribution"),
+			Version:               column(res, i, "Version"),
+		})
+	}
+}
+
+func (s *Snapshot) collectServices(executor Executor) {
+	for _, role := range []string{"GRAPH", "META", "STORAGE"} {
+		res, err := execute(executor, "SHOW HOSTS "+role)
+		if err != nil {
+			s.Errors = append(s.Errors, err.Error())
+			continue
+		}
+		for i := 0; i < res.GetRowSize(); i++ {
+			s.Services = append(s.Services, Service{
+				Role:    role,
+				Address: address(res, i),
+				Status:  column(res, i, "Status"),
+				Version: column(res, i, "Version"),
+			})
+		}
+	}
+}
+
+// collectJobs lists the jobs of the current space, the tasks are counted for the running ones
+func (s *Snapshot) collectJobs(executor Executor) {
+	if s.Space == "" {
+		return
+	}
+	res, err := execute(executor, "SHOW JOBS")
+	if err != nil {
+		s.Errors = append(s.Errors, err.Error())
+		return
+	}
+	for i := 0; i < res.GetRowSize(); i++ {
+		job := Job{
+			ID:        intColumn(res, i, "Job Id"),
+			Command:   column(res, i, "Command"),
+			Status:    column(res, i, "Status"),
+			StartTime: column(res, i, "Start Time"),
+		}
+		if job.Status == "RUNNING" {
+			// The first row is the job, and the others are its tasks
+			tasks, err := execute(executor, fmt.Sprintf("SHOW JOB %d", job.ID))
+			if err == nil {
+				for j := 1; j < tasks.GetRowSize(); j++ {
+					job.Tasks++
+					if column(tasks, j, "Status") == "FINISHED" {
+						job.Finished++
+					}
+				}
+			}
+		}
+		s.Jobs = append(s.Jobs, job)
+	}
+}
+
+// queries returns the running queries of SHOW QUERIES or SHOW LOCAL QUERIES
+func queries(executor Executor, stmt string) ([]Query, error) {
+	res, err := execute(executor, stmt)
+	if err != nil {
+		return nil, err
+	}
+	var queries []Query
+	for i := 0; i < res.GetRowSize(); i++ {
+		queries = append(queries, Query{
+			SessionID: intColumn(res, i, "SessionID"),
+			PlanID:    intColumn(res, i, "ExecutionPlanID"),
+			User:      column(res, i, "User"),
+			Host:      column(res, i, "Host"),
+			Duration:  time.Duration(intColumn(res, i, "DurationInUSec")) * time.Microsecond,
+			Status:    column(res, i, "Status"),
+			Statement: column(res, i, "Query"),
+		})
+	}
+	return queries, nil
+}
+
+// SessionQueries returns the running queries of the session. The graphd connected by the
+// executor is looked up first by SHOW LOCAL QUERIES, and then all of them by SHOW QUERIES.
+func SessionQueries(executor Executor, sessionID int64) ([]Query, error) {
+	var found []Query
+	for _, stmt := range []string{"SHOW LOCAL QUERIES", "SHOW QUERIES"} {
+		all, err := queries(executor, stmt)
+		if err != nil {
+			return nil, err
+		}
+		for _, q := range all {
+			if q.SessionID == sessionID {
+				found = append(found, q)
+			}
+		}
+		if len(found) > 0 {
+			break
+		}
+	}
+	return found, nil
+}
+
+// collectQueries lists the running queries from the slowest, except those of the session itself
+func (s *Snapshot) collectQueries(executor Executor, self int64) {
+	all, err := queries(executor, "SHOW QUERIES")
+	if err != nil {
+		s.Errors = append(s.Errors, err.Error())
+		return
+	}
+	for _, q := range all {
+		if q.SessionID != self {
+			s.Queries = append(s.Queries, q)
+		}
+	}
+	sort.SliceStable(s.Queries, func(i, j int) bool {
+		return s.Queries[i].Duration > s.Queries[j].Duration
+	})
+}
+
+func (s *Snapshot) collectSessions(executor Executor) {
+	res, err := execute(executor, "SHOW SESSIONS")
+	if err != nil {
+		s.Errors = append(s.Errors, err.Error())
+		return
+	}
+	s.Sessions = res.GetRowSize()
+}
+
+// Collect queries the cluster status, the jobs are those of the space, if any.
+// The queries of the session self are excluded.
+func Collect(executor Executor, space string, self int64) *Snapshot {
+	s := &Snapshot{Time: time.Now(), Space: space}
+	s.collectHosts(executor)
+	s.collectServices(executor)
+	s.collectJobs(executor)
+	s.collectQueries(executor, self)
+	s.collectSessions(executor)
+	return s
+}
+
+// Kill kills the query by KILL QUERY, which is the same as Ctrl-C in the console
+func Kill(executor Executor, q Query) error {
+	_, err := execute(executor, fmt.Sprintf("KILL QUERY (session=%d, plan=%d)", q.SessionID, q.PlanID))
+	if err != nil && strings.Contains(err.Error(), "not found") {
+		return fmt.Errorf("query of session %d plan %d is already finished", q.SessionID, q.PlanID)
+	}
+	return err
+}
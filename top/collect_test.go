@@ -0,0 +1,190 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package top
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+	ttypes "github.com/vesoft-inc/nebula-go/v3/nebula"
+	"github.com/vesoft-inc/nebula-go/v3/nebula/graph"
+)
+
+// mockExecutor is a server answering the statements by the responses, the others fail
+type mockExecutor struct {
+	tables   map[string]*graph.ExecutionResponse
+	executed []string
+}
+
+func (m *mockExecutor) Execute(stmt string) (*nebula.ResultSet, error) {
+	m.executed = append(m.executed, stmt)
+	resp, ok := m.tables[stmt]
+	if !ok {
+		resp = &graph.ExecutionResponse{ErrorCode: ttypes.ErrorCode_E_EXECUTION_ERROR, ErrorMsg: []byte("not supported")}
+	}
+	return nebula.GenResultSet(resp)
+}
+
+// response is the result of the columns and the rows, the values are strings or ints
+func response(columns string, rows ...[]interface{}) *graph.ExecutionResponse {
+	data := &ttypes.DataSet{}
+	for _, col := range strings.Split(columns, ",") {
+		data.ColumnNames = append(data.ColumnNames, []byte(col))
+	}
+	for _, row := range rows {
+		r := &ttypes.Row{}
+		for _, v := range row {
+			switch v := v.(type) {
+			case int:
+				i := int64(v)
+				r.Values = append(r.Values, &ttypes.Value{IVal: &i})
+			case string:
+				r.Values = append(r.Values, &ttypes.Value{SVal: []byte(v)})
+			}
+		}
+		data.Rows = append(data.Rows, r)
+	}
+	return &graph.ExecutionResponse{ErrorCode: ttypes.ErrorCode_SUCCEEDED, Data: data}
+}
+
+func row(values ...interface{}) []interface{} {
+	return values
+}
+
+const queryColumns = "SessionID,ExecutionPlanID,User,Host,DurationInUSec,Status,Query"
+
+func newMockExecutor() *mockExecutor {
+	return &mockExecutor{tables: map[string]*graph.ExecutionResponse{
+		"SHOW HOSTS": response("Host,Port,Status,Leader count,Leader distribution,Partition distribution,Version",
+			row("storaged0", 9779, "ONLINE", 10, "nba:10", "nba:10", "3.6.0"),
+			row("storaged1", 9779, "OFFLINE", 0, "No valid partition", "nba:10", "3.6.0")),
+		"SHOW HOSTS GRAPH":   response("Host,Port,Status,Version", row("graphd", 9669, "ONLINE", "3.6.0")),
+		"SHOW HOSTS META":    response("Host,Port,Status,Version", row("metad", 9559, "ONLINE", "3.6.0")),
+		"SHOW HOSTS STORAGE": response("Host,Port,Status,Version", row("storaged0", 9779, "ONLINE", "3.6.0")),
+		"SHOW JOBS": response("Job Id,Command,Status,Start Time,Stop Time",
+			row(2, "REBUILD_TAG_INDEX", "RUNNING", "2023-01-01T00:00:00", ""),
+			row(1, "STATS", "FINISHED", "2023-01-01T00:00:00", "2023-01-01T00:01:00")),
+		"SHOW JOB 2": response("Job Id(TaskId),Command(Dest),Status,Start Time,Stop Time",
+			row(2, "REBUILD_TAG_INDEX", "RUNNING", "", ""),
+			row(0, "storaged0", "FINISHED", "", ""),
+			row(1, "storaged1", "RUNNING", "", "")),
+		"SHOW QUERIES": response(queryColumns,
+			row(1, 10, "root", "graphd:9669", 1000, "RUNNING", "GO FROM 1 OVER e"),
+			row(2, 20, "root", "graphd:9669", 5000, "RUNNING", "MATCH (v) RETURN v"),
+			row(3, 30, "root", "graphd:9669", 9000, "RUNNING", "SHOW QUERIES")),
+		"SHOW LOCAL QUERIES": response(queryColumns),
+		"SHOW SESSIONS":      response("SessionId,UserName", row(1, "root"), row(2, "root"), row(3, "root")),
+	}}
+}
+
+func TestCollect(t *testing.T) {
+	executor := newMockExecutor()
+	s := Collect(executor, "nba", 3)
+
+	wantHosts := []Host{
+		{Address: "storaged0:9779", Status: "ONLINE", Leaders: 10, LeaderDistribution: "nba:10", PartitionDistribution: "nba:10", Version: "3.6.0"},
+		{Address: "storaged1:9779", Status: "OFFLINE", LeaderDistribution: "No valid partition", PartitionDistribution: "nba:10", Version: "3.6.0"},
+	}
+	wantServices := []Service{
+		{Role: "GRAPH", Address: "graphd:9669", Status: "ONLINE", Version: "3.6.0"},
+		{Role: "META", Address: "metad:9559", Status: "ONLINE", Version: "3.6.0"},
+		{Role: "STORAGE", Address: "storaged0:9779", Status: "ONLINE", Version: "3.6.0"},
+	}
+	wantJobs := []Job{
+		{ID: 2, Command: "REBUILD_TAG_INDEX", Status: "RUNNING", StartTime: "2023-01-01T00:00:00", Tasks: 2, Finished: 1},
+		{ID: 1, Command: "STATS", Status: "FINISHED", StartTime: "2023-01-01T00:00:00"},
+	}
+	// The queries are ordered from the slowest, and those of the session itself are excluded
+	wantQueries := []Query{
+		{SessionID: 2, PlanID: 20, User: "root", Host: "graphd:9669", Duration: 5 * time.Millisecond, Status: "RUNNING", Statement: "MATCH (v) RETURN v"},
+		{SessionID: 1, PlanID: 10, User: "root", Host: "graphd:9669", Duration: time.Millisecond, Status: "RUNNING", Statement: "GO FROM 1 OVER e"},
+	}
+	for _, c := range []struct {
+		name      string
+		got, want interface{}
+	}{
+		{"Hosts", s.Hosts, wantHosts},
+		{"Services", s.Services, wantServices},
+		{"Jobs", s.Jobs, wantJobs},
+		{"Queries", s.Queries, wantQueries},
+		{"Sessions", s.Sessions, 3},
+		{"Errors", s.Errors, []string(nil)},
+	} {
+		if !reflect.DeepEqual(c.got, c.want) {
+			t.Errorf("%s = %+v, want %+v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestCollectErrors(t *testing.T) {
+	executor := newMockExecutor()
+	delete(executor.tables, "SHOW HOSTS META")
+	delete(executor.tables, "SHOW SESSIONS")
+	// The jobs are not collected without a space
+	s := Collect(executor, "", 0)
+	if len(s.Errors) != 2 || !strings.Contains(s.Errors[0], "SHOW HOSTS META") || !strings.Contains(s.Errors[1], "SHOW SESSIONS") {
+		t.Errorf("Errors = %q, want those of SHOW HOSTS META and SHOW SESSIONS", s.Errors)
+	}
+	// The others are still collected
+	if len(s.Hosts) != 2 || len(s.Services) != 2 || len(s.Queries) != 3 || s.Jobs != nil {
+		t.Errorf("Collect = %+v", s)
+	}
+	for _, stmt := range executor.executed {
+		if strings.HasPrefix(stmt, "SHOW JOB") {
+			t.Errorf("%s is executed without a space", stmt)
+		}
+	}
+}
+
+func TestSessionQueries(t *testing.T) {
+	cases := []struct {
+		local   *graph.ExecutionResponse
+		session int64
+		want    []int64
+	}{
+		// Looked up in all graphd if not found on the local one
+		{response(queryColumns), 2, []int64{20}},
+		{response(queryColumns, row(2, 21, "root", "graphd:9669", 1, "RUNNING", "GO")), 2, []int64{21}},
+		{response(queryColumns), 4, nil},
+	}
+	for _, c := range cases {
+		executor := newMockExecutor()
+		executor.tables["SHOW LOCAL QUERIES"] = c.local
+		queries, err := SessionQueries(executor, c.session)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var plans []int64
+		for _, q := range queries {
+			plans = append(plans, q.PlanID)
+		}
+		if !reflect.DeepEqual(plans, c.want) {
+			t.Errorf("SessionQueries(%d) = %v, want %v", c.session, plans, c.want)
+		}
+	}
+}
+
+func TestKill(t *testing.T) {
+	executor := newMockExecutor()
+	stmt := "KILL QUERY (session=1, plan=10)"
+	executor.tables[stmt] = &graph.ExecutionResponse{ErrorCode: ttypes.ErrorCode_SUCCEEDED}
+	if err := Kill(executor, Query{SessionID: 1, PlanID: 10}); err != nil {
+		t.Errorf("Kill failed, %s", err)
+	}
+	if got := executor.executed; !reflect.DeepEqual(got, []string{stmt}) {
+		t.Errorf("executed %q, want %q", got, stmt)
+	}
+
+	executor.tables[stmt] = &graph.ExecutionResponse{ErrorCode: ttypes.ErrorCode_E_EXECUTION_ERROR, ErrorMsg: []byte("Session `1' not found")}
+	err := Kill(executor, Query{SessionID: 1, PlanID: 10})
+	if want := fmt.Sprintf("query of session %d plan %d is already finished", 1, 10); err == nil || err.Error() != want {
+		t.Errorf("Kill error = %v, want %q", err, want)
+	}
+}
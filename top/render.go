@@ -0,0 +1,110 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package top
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jedib0t/go-pretty/v6/table"
+	"github.com/jedib0t/go-pretty/v6/text"
+)
+
+// The queries shown at most, from the slowest
+const maxQueries = 10
+
+func newTable(width int, header ...interface{}) table.Writer {
+	t := table.NewWriter()
+	t.Style().Format.Header = text.FormatDefault
+	if width > 0 {
+		t.SetAllowedRowLength(width)
+	}
+	t.AppendHeader(table.Row(header))
+	return t
+}
+
+func progress(job Job) string {
+	if job.Status != "RUNNING" || job.Tasks == 0 {
+		return ""
+	}
+	const width = 20
+	done := job.Finished * width / job.Tasks
+	return fmt.Sprintf("[%s%s] %d/%d", strings.Repeat("=", done), strings.Repeat(" ", width-done), job.Finished, job.Tasks)
+}
+
+func status(s string) string {
+	switch s {
+	case "ONLINE", "FINISHED":
+		return text.FgGreen.Sprint(s)
+	case "OFFLINE", "FAILED", "STOPPED":
+		return text.FgRed.Sprint(s)
+	case "RUNNING", "QUEUE":
+		return text.FgYellow.Sprint(s)
+	}
+	return s
+}
+
+// Render draws the snapshot in the width of the terminal, the selected query is highlighted
+func Render(s *Snapshot, width, selected int) string {
+	var b strings.Builder
+	space := s.Space
+	if space == "" {
+		space = "(none)"
+	}
+	fmt.Fprintf(&b, "%s    space: %s    sessions: %d    running queries: %d\n\n",
+		s.Time.Format("2006-01-02 15:04:05"), space, s.Sessions, len(s.Queries))
+
+	services := newTable(width, "Role", "Address", "Status", "Version")
+	for _, svc := range s.Services {
+		services.AppendRow(table.Row{svc.Role, svc.Address, status(svc.Status), svc.Version})
+	}
+	b.WriteString(services.Render())
+	b.WriteString("\n\n")
+
+	hosts := newTable(width, "Storage Host", "Status", "Leaders", "Leader distribution", "Partition distribution")
+	for _, h := range s.Hosts {
+		hosts.AppendRow(table.Row{h.Address, status(h.Status), h.Leaders, h.LeaderDistribution, h.PartitionDistribution})
+	}
+	b.WriteString(hosts.Render())
+	b.WriteString("\n\n")
+
+	if s.Space == "" {
+		b.WriteString("Jobs are not shown, please choose a graph space with `USE spaceName' first\n\n")
+	} else {
+		jobs := newTable(width, "Job Id", "Command", "Status", "Start Time", "Progress")
+		for _, j := range s.Jobs {
+			if j.Status != "RUNNING" && j.Status != "QUEUE" {
+				continue
+			}
+			jobs.AppendRow(table.Row{j.ID, j.Command, status(j.Status), j.StartTime, progress(j)})
+		}
+		b.WriteString(jobs.Render())
+		b.WriteString("\n\n")
+	}
+
+	queries := newTable(width, "", "Session", "Plan", "User", "Host", "Duration", "Status", "Query")
+	for i, q := range s.Queries {
+		if i >= maxQueries {
+			break
+		}
+		row := table.Row{" ", q.SessionID, q.PlanID, q.User, q.Host, q.Duration, q.Status, q.Statement}
+		if i == selected {
+			row[0] = ">"
+			for j := range row {
+				row[j] = text.Colors{text.BgWhite, text.FgBlack}.Sprint(row[j])
+			}
+		}
+		queries.AppendRow(row)
+	}
+	b.WriteString(queries.Render())
+	b.WriteString("\n")
+
+	for _, e := range s.Errors {
+		b.WriteString(text.FgRed.Sprint(e))
+		b.WriteString("\n")
+	}
+	return b.String()
+}
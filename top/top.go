@@ -0,0 +1,159 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package top
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	prompt "github.com/c-bata/go-prompt"
+)
+
+// DefaultInterval is the interval of refreshing the dashboard
+const DefaultInterval = 2 * time.Second
+
+// The interval of polling the keys
+const keyInterval = 50 * time.Millisecond
+
+const help = "q: quit  ↑/↓: select query  k: kill query  r: refresh"
+
+// Dashboard is the full-screen status of the cluster shown by :top
+type Dashboard struct {
+	// Killer executes KILL QUERY instead of the executor polling the status if it's set,
+	// i.e. the one writing the audit log
+	Killer Executor
+
+	executor Executor
+	space    string
+	self     int64
+	interval time.Duration
+
+	snapshot *Snapshot
+	selected int
+	// the message shown under the dashboard, i.e. the result of killing a query
+	message string
+	// the query to kill after the confirmation
+	killing *Query
+}
+
+// NewDashboard creates a dashboard, the jobs of the space are shown, and the queries
+// of the session self are excluded
+func NewDashboard(executor Executor, space string, self int64, interval time.Duration) *Dashboard {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return &Dashboard{executor: executor, space: space, self: self, interval: interval}
+}
+
+func (d *Dashboard) refresh() {
+	d.snapshot = Collect(d.executor, d.space, d.self)
+	if d.selected >= len(d.snapshot.Queries) {
+		d.selected = len(d.snapshot.Queries) - 1
+	}
+	if d.selected < 0 {
+		d.selected = 0
+	}
+}
+
+func (d *Dashboard) draw(width int) {
+	var b strings.Builder
+	// Clear the screen and move the cursor to the top left
+	b.WriteString("\033[H\033[2J")
+	b.WriteString(Render(d.snapshot, width, d.selected))
+	b.WriteString("\n")
+	if d.killing != nil {
+		fmt.Fprintf(&b, "Kill the query of session %d plan %d? [y/N]", d.killing.SessionID, d.killing.PlanID)
+	} else {
+		if d.message != "" {
+			b.WriteString(d.message)
+			b.WriteString("\n")
+		}
+		b.WriteString(help)
+	}
+	// The terminal is in raw mode, so the lines are returned to the beginning explicitly
+	fmt.Print(strings.ReplaceAll(b.String(), "\n", "\r\n"))
+}
+
+// handle processes a key, and returns whether to quit
+func (d *Dashboard) handle(b []byte) (quit bool) {
+	key := prompt.GetKey(b)
+	if d.killing != nil {
+		if string(b) == "y" || string(b) == "Y" {
+			killer := d.Killer
+			if killer == nil {
+				killer = d.executor
+			}
+			if err := Kill(killer, *d.killing); err != nil {
+				d.message = "Error: " + err.Error()
+			} else {
+				d.message = fmt.Sprintf("Query of session %d plan %d is killed", d.killing.SessionID, d.killing.PlanID)
+			}
+			d.refresh()
+		}
+		d.killing = nil
+		return false
+	}
+	switch {
+	case key == prompt.ControlC || key == prompt.Escape || string(b) == "q":
+		return true
+	case key == prompt.Up:
+		if d.selected > 0 {
+			d.selected--
+		}
+	case key == prompt.Down:
+		if d.selected < len(d.snapshot.Queries)-1 && d.selected < maxQueries-1 {
+			d.selected++
+		}
+	case string(b) == "k":
+		if len(d.snapshot.Queries) == 0 {
+			d.message = "No query to kill"
+		} else {
+			q := d.snapshot.Queries[d.selected]
+			d.killing = &q
+		}
+	case string(b) == "r":
+		d.message = ""
+		d.refresh()
+	}
+	return false
+}
+
+// Run shows the dashboard until q, Esc or Ctrl-C is pressed
+func (d *Dashboard) Run() error {
+	parser := prompt.NewStandardInputParser()
+	if err := parser.Setup(); err != nil {
+		return err
+	}
+	defer func() {
+		parser.TearDown()
+		fmt.Print("\033[H\033[2J")
+	}()
+
+	d.refresh()
+	d.draw(int(parser.GetWinSize().Col))
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			d.refresh()
+			d.draw(int(parser.GetWinSize().Col))
+			continue
+		default:
+		}
+		// The input is non-blocking, and an error is returned if there is nothing to read
+		b, err := parser.Read()
+		if err != nil || len(b) == 0 {
+			time.Sleep(keyInterval)
+			continue
+		}
+		if d.handle(b) {
+			return nil
+		}
+		d.draw(int(parser.GetWinSize().Col))
+	}
+}
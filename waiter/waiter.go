@@ -0,0 +1,258 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package waiter
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/vesoft-inc/nebula-console/ngql"
+	nebula "github.com/vesoft-inc/nebula-go/v3"
+)
+
+const (
+	KindSpace     = "SPACE"
+	KindTag       = "TAG"
+	KindEdge      = "EDGE"
+	KindTagIndex  = "TAG INDEX"
+	KindEdgeIndex = "EDGE INDEX"
+	// KindIndex is a tag index or an edge index, which is resolved by name
+	KindIndex = "INDEX"
+)
+
+// DefaultTimeout is the longest time to wait for an object
+const DefaultTimeout = 60 * time.Second
+
+// The interval of polling
+const interval = 500 * time.Millisecond
+
+// Object is a space, a tag, an edge type or an index, which is usable after the
+// schema change is propagated to graphd and storaged by heartbeat
+type Object struct {
+	Kind  string
+	Space string
+	Name  string
+	// the tag or edge type of an index
+	Schema string
+}
+
+func (o Object) String() string {
+	return fmt.Sprintf("%s %s", strings.ToLower(o.Kind), o.Name)
+}
+
+func execute(session *nebula.Session, stmt string) (*nebula.ResultSet, error) {
+	res, err := session.Execute(stmt)
+	if err != nil {
+		return nil, err
+	}
+	if !res.IsSucceed() {
+		return nil, fmt.Errorf("an error occurred when executing: %s, [ERROR (%d)]: %s", stmt, res.GetErrorCode(), res.GetErrorMsg())
+	}
+	return res, nil
+}
+
+var (
+	name         = "`?([^`\\s(;]+)`?"
+	createRegexp = regexp.MustCompile(`(?is)^\s*CREATE\s+(SPACE|TAG\s+INDEX|EDGE\s+INDEX|TAG|EDGE)\s+(?:IF\s+NOT\s+EXISTS\s+)?` + name + `(?:\s+ON\s+` + name + `)?`)
+	useRegexp    = regexp.MustCompile(`(?is)^\s*USE\s+` + name + `\s*$`)
+	spacesRegexp = regexp.MustCompile(`\s+`)
+)
+
+// Parse returns the objects created by the statements in the line, the space is the current space
+// before the line, which is switched by the USE statements in it, i.e. USE s2; CREATE TAG t(...).
+// The statements are split by the tokenizer, so the semicolons in the strings and comments are kept.
+func Parse(line, space string) []Object {
+	var objects []Object
+	for _, stmt := range ngql.Split(line) {
+		if m := useRegexp.FindStringSubmatch(stmt); m != nil {
+			space = m[1]
+			continue
+		}
+		m := createRegexp.FindStringSubmatch(stmt)
+		if m == nil {
+			continue
+		}
+		o := Object{Kind: strings.ToUpper(spacesRegexp.ReplaceAllString(m[1], " ")), Space: space, Name: m[2], Schema: m[3]}
+		if o.Kind == KindSpace {
+			o.Space = o.Name
+		}
+		objects = append(objects, o)
+	}
+	return objects
+}
+
+// vidLiteral returns a vid of the space which is unlikely to exist
+func vidLiteral(session *nebula.Session, space string) (string, error) {
+	res, err := execute(session, fmt.Sprintf("DESCRIBE SPACE `%s`", space))
+	if err != nil {
+		return "", err
+	}
+	record, err := res.GetRowValuesByIndex(0)
+	if err != nil {
+		return "", err
+	}
+	val, err := record.GetValueByColName("Vid Type")
+	if err != nil {
+		return "", err
+	}
+	vidType, _ := val.AsString()
+	if strings.HasPrefix(strings.ToUpper(vidType), "INT") {
+		return "0", nil
+	}
+	return `"__nebula_console_wait__"`, nil
+}
+
+// Ready probes the object once, the statements reach storaged for tags, edge types and
+// indexes, which fail until storaged knows the schema. The session is switched to the space.
+func Ready(session *nebula.Session, o Object) error {
+	if _, err := execute(session, fmt.Sprintf("USE `%s`", o.Space)); err != nil {
+		return err
+	}
+	var stmt string
+	switch o.Kind {
+	case KindSpace:
+		return nil
+	case KindIndex:
+		resolved, err := ResolveIndex(session, o.Space, o.Name)
+		if err != nil {
+			return err
+		}
+		return Ready(session, resolved)
+	case KindTag:
+		vid, err := vidLiteral(session, o.Space)
+		if err != nil {
+			return err
+		}
+		stmt = fmt.Sprintf("FETCH PROP ON `%s` %s YIELD vertex AS v", o.Name, vid)
+	case KindEdge:
+		vid, err := vidLiteral(session, o.Space)
+		if err != nil {
+			return err
+		}
+		stmt = fmt.Sprintf("FETCH PROP ON `%s` %s->%s YIELD edge AS e", o.Name, vid, vid)
+	case KindTagIndex, KindEdgeIndex:
+		kind := "TAG"
+		if o.Kind == KindEdgeIndex {
+			kind = "EDGE"
+		}
+		if _, err := execute(session, fmt.Sprintf("DESCRIBE %s INDEX `%s`", kind, o.Name)); err != nil {
+			return err
+		}
+		if err := rebuilt(session, kind, o.Name); err != nil {
+			return err
+		}
+		if o.Schema == "" {
+			return nil
+		}
+		yield := "id(vertex)"
+		if kind == "EDGE" {
+			yield = "src(edge)"
+		}
+		stmt = fmt.Sprintf("LOOKUP ON `%s` YIELD %s | LIMIT 1", o.Schema, yield)
+	default:
+		return fmt.Errorf("unknown kind %s", o.Kind)
+	}
+	_, err := execute(session, stmt)
+	return err
+}
+
+func column(res *nebula.ResultSet, row int, col string) string {
+	record, err := res.GetRowValuesByIndex(row)
+	if err != nil {
+		return ""
+	}
+	val, err := record.GetValueByColName(col)
+	if err != nil {
+		return ""
+	}
+	if s, err := val.AsString(); err == nil {
+		return s
+	}
+	return val.String()
+}
+
+// rebuilt checks the status of the latest rebuilding of the index, if any
+func rebuilt(session *nebula.Session, kind, index string) error {
+	res, err := execute(session, fmt.Sprintf("SHOW %s INDEX STATUS", kind))
+	if err != nil {
+		return err
+	}
+	for i := 0; i < res.GetRowSize(); i++ {
+		if column(res, i, "Name") != index {
+			continue
+		}
+		if status := column(res, i, "Index Status"); status != "FINISHED" {
+			return fmt.Errorf("rebuilding of index %s is %s", index, status)
+		}
+	}
+	return nil
+}
+
+// ResolveIndex finds the kind and the tag or edge type of the index in the space
+func ResolveIndex(session *nebula.Session, space, index string) (Object, error) {
+	if _, err := execute(session, fmt.Sprintf("USE `%s`", space)); err != nil {
+		return Object{}, err
+	}
+	for _, kind := range []string{KindTagIndex, KindEdgeIndex} {
+		res, err := execute(session, fmt.Sprintf("SHOW %sES", kind))
+		if err != nil {
+			return Object{}, err
+		}
+		by := "By Tag"
+		if kind == KindEdgeIndex {
+			by = "By Edge"
+		}
+		for i := 0; i < res.GetRowSize(); i++ {
+			if column(res, i, "Index Name") == index {
+				return Object{Kind: kind, Space: space, Name: index, Schema: column(res, i, by)}, nil
+			}
+		}
+	}
+	return Object{}, fmt.Errorf("index %s not found in space %s", index, space)
+}
+
+// WaitJob polls the job until it is finished or the timeout expires
+func WaitJob(session *nebula.Session, job int64, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		res, err := execute(session, fmt.Sprintf("SHOW JOB %d", job))
+		if err != nil {
+			return err
+		}
+		// The first row is the job, and the others are its tasks
+		status := strings.ToUpper(column(res, 0, "Status"))
+		switch status {
+		case "FINISHED":
+			return nil
+		case "FAILED", "STOPPED":
+			return fmt.Errorf("job %d is %s", job, strings.ToLower(status))
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("job %d is not finished in %v, the status is %s", job, timeout, status)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// Wait polls the objects until they are all ready or the timeout expires
+func Wait(session *nebula.Session, objects []Object, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for _, o := range objects {
+		for {
+			err := Ready(session, o)
+			if err == nil {
+				break
+			}
+			if time.Now().After(deadline) {
+				return fmt.Errorf("%s is not ready in %v, %s", o.String(), timeout, err.Error())
+			}
+			time.Sleep(interval)
+		}
+	}
+	return nil
+}
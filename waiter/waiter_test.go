@@ -0,0 +1,50 @@
+/* Copyright (c) 2023 vesoft inc. All rights reserved.
+ *
+ * This source code is licensed under Apache 2.0 License.
+ */
+
+package waiter
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	cases := []struct {
+		line  string
+		space string
+		want  []Object
+	}{
+		{"SHOW TAGS", "nba", nil},
+		{"CREATE TAG player(name string)", "nba", []Object{{Kind: KindTag, Space: "nba", Name: "player"}}},
+		{"create edge if not exists `follow`(degree int)", "nba", []Object{{Kind: KindEdge, Space: "nba", Name: "follow"}}},
+		{"CREATE SPACE s2(vid_type = INT64)", "nba", []Object{{Kind: KindSpace, Space: "s2", Name: "s2"}}},
+		{"CREATE TAG  INDEX i ON player(name(10))", "nba", []Object{{Kind: KindTagIndex, Space: "nba", Name: "i", Schema: "player"}}},
+		{"CREATE EDGE INDEX IF NOT EXISTS `j` ON `follow`()", "nba", []Object{{Kind: KindEdgeIndex, Space: "nba", Name: "j", Schema: "follow"}}},
+		// The objects are created in the space switched by USE in the line
+		{"CREATE TAG t1(); USE s2; CREATE TAG t2(); use `s3`;CREATE EDGE e()", "nba", []Object{
+			{Kind: KindTag, Space: "nba", Name: "t1"},
+			{Kind: KindTag, Space: "s2", Name: "t2"},
+			{Kind: KindEdge, Space: "s3", Name: "e"},
+		}},
+		{"CREATE SPACE s2(vid_type = INT64); USE s2; CREATE TAG t()", "(none)", []Object{
+			{Kind: KindSpace, Space: "s2", Name: "s2"},
+			{Kind: KindTag, Space: "s2", Name: "t"},
+		}},
+		{"INSERT VERTEX t() VALUES 1:(); CREATE TAG t()", "nba", []Object{{Kind: KindTag, Space: "nba", Name: "t"}}},
+		// The semicolons in the strings and the comments don't separate the statements
+		{`INSERT VERTEX t(a) VALUES 1:("x; CREATE TAG fake()"); CREATE TAG t2()`, "nba", []Object{{Kind: KindTag, Space: "nba", Name: "t2"}}},
+		{"CREATE TAG t1() COMMENT = 'a;b'; CREATE EDGE e()", "nba", []Object{
+			{Kind: KindTag, Space: "nba", Name: "t1"},
+			{Kind: KindEdge, Space: "nba", Name: "e"},
+		}},
+		{"CREATE TAG t1() /* ; CREATE TAG fake() */", "nba", []Object{{Kind: KindTag, Space: "nba", Name: "t1"}}},
+		{"CREATE TAG t1() # ; USE s2", "nba", []Object{{Kind: KindTag, Space: "nba", Name: "t1"}}},
+	}
+	for _, c := range cases {
+		if got := Parse(c.line, c.space); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("Parse(%q, %q) = %+v, want %+v", c.line, c.space, got, c.want)
+		}
+	}
+}